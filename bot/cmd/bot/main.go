@@ -10,6 +10,7 @@ import (
 	"crypto-bot/internal/calendar"
 	"crypto-bot/internal/config"
 	"crypto-bot/internal/notify"
+	"crypto-bot/internal/strategy"
 )
 
 func main() {
@@ -37,33 +38,39 @@ func main() {
 		return
 	}
 
-	agg := calendar.NewAggregator("data/events.json", struct {
-		Binance      bool
-		Bybit        bool
-		OKX          bool
-		TokenUnlocks bool
-		Airdrops     bool
-	}{
-		Binance:      cfg.Sources.Binance,
-		Bybit:        cfg.Sources.Bybit,
-		OKX:          cfg.Sources.OKX,
-		TokenUnlocks: cfg.Sources.TokenUnlocks,
-		Airdrops:     cfg.Sources.Airdrops,
-	})
+	agg, err := calendar.NewAggregator(cfg)
+	if err != nil {
+		// Хранилище не поднялось даже из резервной копии — без него бот
+		// заново разослал бы все алерты, поэтому останавливаемся
+		if sendErr := tg.SendPlain("❌ Бот не запущен: " + err.Error()); sendErr != nil {
+			log.Printf("[main] telegram send failed: %v", sendErr)
+		}
+		log.Fatalf("failed to create aggregator: %v", err)
+	}
+	if err := agg.StorageRecovered(); err != nil {
+		if sendErr := tg.Send(notify.FormatStorageRecovered(err)); sendErr != nil {
+			log.Printf("[main] telegram send failed: %v", sendErr)
+		}
+	}
 
 	log.Println("Crypto Calendar Bot started")
 	log.Printf("Refresh interval: %d min", cfg.Scanner.RefreshIntervalMinutes)
 
 	refreshTicker := time.NewTicker(time.Duration(cfg.Scanner.RefreshIntervalMinutes) * time.Minute)
 	hourTicker := time.NewTicker(time.Hour)
+	strategiesTicker := time.NewTicker(cfg.Strategies.ReloadInterval())
 	defer refreshTicker.Stop()
 	defer hourTicker.Stop()
+	defer strategiesTicker.Stop()
 
 	// Сразу при старте обновляем данные
 	ctx := context.Background()
 	log.Println("[main] initial data refresh...")
 	agg.Refresh(ctx)
 	log.Printf("[main] loaded %d events", len(agg.Events()))
+	go enrichTokenomics(ctx, agg)
+	checkSources(tg, agg, cfg.Scanner.FailureAlertAfter)
+	checkUpdates(tg, agg)
 
 	// Удаляем webhook — иначе getUpdates конфликтует с ним и не получает сообщения
 	if err := tg.DeleteWebhook(); err != nil {
@@ -85,15 +92,39 @@ func main() {
 			log.Println("[main] refreshing data...")
 			agg.Refresh(ctx)
 			log.Printf("[main] %d events in cache", len(agg.Events()))
+			go enrichTokenomics(ctx, agg)
+			checkSources(tg, agg, cfg.Scanner.FailureAlertAfter)
+			checkUpdates(tg, agg)
 
 		case <-hourTicker.C:
 			checkDigest(tg, agg, cfg.Schedule.DigestWeekday, cfg.Schedule.DigestTimeUTC)
+			checkOutcomeReport(tg, agg, cfg.Schedule.DigestWeekday, cfg.Schedule.DigestTimeUTC)
 			checkAlerts24h(tg, agg)
 			checkAlerts2h(tg, agg)
+			trackOutcomes(ctx, agg)
+
+		case <-strategiesTicker.C:
+			reloadStrategies(tg, agg)
 		}
 	}
 }
 
+// reloadStrategies перечитывает изменённый файл стратегий; если файл с
+// ошибкой — предупреждает в Telegram, стратегии остаются прежними.
+func reloadStrategies(tg *notify.Telegram, agg *calendar.Aggregator) {
+	ok, err := agg.ReloadStrategies()
+	if err != nil {
+		log.Printf("[strategies] reload failed, keeping previous: %v", err)
+		if sendErr := tg.Send(notify.FormatStrategiesInvalid(err)); sendErr != nil {
+			log.Printf("[strategies] send error: %v", sendErr)
+		}
+		return
+	}
+	if ok {
+		log.Printf("[strategies] reloaded %d strategies from %s", len(agg.StrategyList()), agg.StrategiesFile())
+	}
+}
+
 // startPolling receives Telegram updates and dispatches commands to the handler.
 func startPolling(tg *notify.Telegram, handler *notify.CommandHandler) {
 	var offset int64
@@ -116,8 +147,61 @@ func startPolling(tg *notify.Telegram, handler *notify.CommandHandler) {
 
 // checkDigest отправляет понедельничный дайджест если сейчас нужное время
 func checkDigest(tg *notify.Telegram, agg *calendar.Aggregator, weekday, timeUTC string) {
+	if !isScheduled(time.Now().UTC(), weekday, timeUTC) {
+		return
+	}
+
+	events := calendar.EventsForDigest(agg.Events(), agg.Windows(), agg.Unlocks())
+	weekStart, weekEnd := calendar.DigestPeriod(agg.Windows())
+
+	msg := notify.FormatDigest(events, weekStart, weekEnd)
+	if err := tg.Send(msg); err != nil {
+		log.Printf("[digest] send error: %v", err)
+		return
+	}
+
+	// Помечаем все события как отправленные в дайджест
+	for _, e := range events {
+		agg.MarkSentDigest(e)
+	}
+	log.Printf("[digest] sent with %d events", len(events))
+}
+
+// outcomeReportPeriod — за сколько прошедших недель сводить реакцию цены:
+// у событий последней недели ещё нет цены через 7 дней
+const outcomeReportPeriod = 28 * 24 * time.Hour
+
+// checkOutcomeReport отправляет еженедельный отчёт о реакции цены на события
+// в то же время, что и дайджест
+func checkOutcomeReport(tg *notify.Telegram, agg *calendar.Aggregator, weekday, timeUTC string) {
 	now := time.Now().UTC()
+	if !isScheduled(now, weekday, timeUTC) {
+		return
+	}
+	from := now.Add(-outcomeReportPeriod)
+	if err := tg.Send(notify.FormatOutcomeReport(agg.OutcomeReport(from, now), from, now)); err != nil {
+		log.Printf("[outcomes] report send error: %v", err)
+		return
+	}
+	log.Println("[outcomes] weekly report sent")
+}
+
+// enrichTokenomics дописывает токеномику событиям в фоне после обновления
+func enrichTokenomics(ctx context.Context, agg *calendar.Aggregator) {
+	if n := agg.EnrichTokenomics(ctx); n > 0 {
+		log.Printf("[main] tokenomics updated for %d events", n)
+	}
+}
 
+// trackOutcomes дописывает реакцию цены прошедшим событиям
+func trackOutcomes(ctx context.Context, agg *calendar.Aggregator) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	agg.TrackOutcomes(ctx)
+}
+
+// isScheduled сообщает, совпадают ли день недели и час now с расписанием
+func isScheduled(now time.Time, weekday, timeUTC string) bool {
 	targetDay := strings.ToLower(weekday)
 	var wantDay time.Weekday
 	switch targetDay {
@@ -136,52 +220,56 @@ func checkDigest(tg *notify.Telegram, agg *calendar.Aggregator, weekday, timeUTC
 	}
 
 	if now.Weekday() != wantDay {
-		return
+		return false
 	}
 
 	// Парсим целевое время
 	parts := strings.Split(timeUTC, ":")
 	if len(parts) != 2 {
-		return
+		return false
 	}
 	var h, m int
 	if _, err := parseIntPair(parts[0], parts[1], &h, &m); err != nil {
-		return
+		return false
 	}
 
 	// Проверяем: текущий час совпадает с настроенным временем
-	if now.Hour() != h {
-		return
-	}
-
-	events := calendar.EventsForDigest(agg.Events())
-	now = time.Now().UTC()
-	weekStart := now
-	weekEnd := now.Add(7 * 24 * time.Hour)
+	return now.Hour() == h
+}
 
-	msg := notify.FormatDigest(events, weekStart, weekEnd)
-	if err := tg.Send(msg); err != nil {
-		log.Printf("[digest] send error: %v", err)
-		return
+// checkSources предупреждает об источниках, которые падают threshold обновлений подряд.
+// Предупреждение разовое: повторно придёт только после восстановления и нового падения.
+func checkSources(tg *notify.Telegram, agg *calendar.Aggregator, threshold int) {
+	for _, s := range agg.FailingSources(threshold) {
+		if err := tg.Send(notify.FormatSourceDown(s)); err != nil {
+			log.Printf("[health] send error for %s: %v", s.Name, err)
+			continue
+		}
+		log.Printf("[health] source %s down for %d refreshes", s.Name, s.Failures)
 	}
+}
 
-	// Помечаем все события как отправленные в дайджест
-	for _, e := range events {
-		agg.MarkSentDigest(e.ID)
+// checkUpdates отправляет уведомления о перенесённых и пропавших событиях
+func checkUpdates(tg *notify.Telegram, agg *calendar.Aggregator) {
+	for _, u := range agg.TakeUpdates() {
+		if err := tg.Send(notify.FormatUpdated(u.Event, u.Changes)); err != nil {
+			log.Printf("[updates] send error for %s: %v", u.Event.ID, err)
+			continue
+		}
+		log.Printf("[updates] sent for %s", u.Event.ID)
 	}
-	log.Printf("[digest] sent with %d events", len(events))
 }
 
 // checkAlerts24h проверяет события завтра и отправляет алерты
 func checkAlerts24h(tg *notify.Telegram, agg *calendar.Aggregator) {
-	events := calendar.EventsTomorrow(agg.Events())
+	events := calendar.EventsTomorrow(agg.Events(), agg.Unlocks())
 	for _, e := range events {
-		msg := notify.FormatAlert24h(e)
+		msg := notify.FormatAlert24h(e, agg.Strategies(e, strategy.Alert24h))
 		if err := tg.Send(msg); err != nil {
 			log.Printf("[alert24h] send error for %s: %v", e.ID, err)
 			continue
 		}
-		agg.MarkSent24h(e.ID)
+		agg.MarkSent24h(e)
 		log.Printf("[alert24h] sent for %s", e.ID)
 	}
 }
@@ -190,12 +278,12 @@ func checkAlerts24h(tg *notify.Telegram, agg *calendar.Aggregator) {
 func checkAlerts2h(tg *notify.Telegram, agg *calendar.Aggregator) {
 	events := calendar.EventsIn2Hours(agg.Events())
 	for _, e := range events {
-		msg := notify.FormatAlert2h(e)
+		msg := notify.FormatAlert2h(e, agg.Strategies(e, strategy.Alert2h))
 		if err := tg.Send(msg); err != nil {
 			log.Printf("[alert2h] send error for %s: %v", e.ID, err)
 			continue
 		}
-		agg.MarkSent2h(e.ID)
+		agg.MarkSent2h(e)
 		log.Printf("[alert2h] sent for %s", e.ID)
 	}
 }
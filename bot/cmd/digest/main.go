@@ -23,32 +23,28 @@ func main() {
 
 	tg := notify.NewTelegram(cfg.Telegram.BotToken, cfg.Telegram.ChatID)
 
-	agg := calendar.NewAggregator("data/events.json", struct {
-		Binance      bool
-		Bybit        bool
-		OKX          bool
-		TokenUnlocks bool
-		Airdrops     bool
-	}{
-		Binance:      cfg.Sources.Binance,
-		Bybit:        cfg.Sources.Bybit,
-		OKX:          cfg.Sources.OKX,
-		TokenUnlocks: cfg.Sources.TokenUnlocks,
-		Airdrops:     cfg.Sources.Airdrops,
-	})
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	agg, err := calendar.NewAggregator(cfg)
+	if err != nil {
+		log.Fatalf("create aggregator: %v", err)
+	}
+	defer agg.Close()
+
+	// Источники опрашиваются параллельно; запас — на сохранение
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Scanner.ScanTimeout()+time.Minute)
 	defer cancel()
 
 	log.Println("Собираем события...")
 	events := agg.Refresh(ctx)
 	log.Printf("Получено %d событий", len(events))
 
-	digestEvents := calendar.EventsForDigest(agg.Events())
+	// Токеномику ждём: дайджест уходит один раз. Свой предел времени — внутри
+	log.Printf("Токеномика обновлена у %d событий", agg.EnrichTokenomics(context.Background()))
+
+	digestEvents := calendar.EventsForDigest(agg.Events(), cfg.Windows, cfg.Unlocks)
 	log.Printf("События для дайджеста: %d", len(digestEvents))
 
-	now := time.Now().UTC()
-	msg := notify.FormatDigest(digestEvents, now, now.Add(7*24*time.Hour))
+	from, to := calendar.DigestPeriod(cfg.Windows)
+	msg := notify.FormatDigest(digestEvents, from, to)
 
 	log.Println("Отправляем в Telegram...")
 	if err := tg.Send(msg); err != nil {
@@ -56,7 +52,7 @@ func main() {
 	}
 
 	for _, e := range digestEvents {
-		agg.MarkSentDigest(e.ID)
+		agg.MarkSentDigest(e)
 	}
 	log.Println("Дайджест отправлен!")
 }
@@ -0,0 +1,98 @@
+// markettest — инструмент для проверки клиентов рыночных данных.
+//
+// Опрашивает все методы market.Client для одной пары. С -record сырые ответы
+// биржи сохраняются в каталог, с -standin клиент ходит в локальный стенд
+// (httptest), который отдаёт сохранённые ответы без сети.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"time"
+
+	"crypto-bot/internal/httpx"
+	"crypto-bot/internal/market"
+)
+
+func main() {
+	provider := flag.String("provider", market.ProviderBybit, "биржа: bybit, binance, okx")
+	base := flag.String("token", "BTC", "базовая валюта пары")
+	quote := flag.String("quote", "USDT", "котируемая валюта пары")
+	record := flag.String("record", "", "сохранить сырые ответы в каталог (напр. internal/market/testdata/bybit)")
+	standin := flag.String("standin", "", "отвечать сохранёнными ответами из каталога через локальный стенд")
+	flag.Parse()
+
+	var opts market.Options
+	switch {
+	case *record != "" && *standin != "":
+		log.Fatal("-record and -standin are mutually exclusive")
+	case *record != "":
+		opts.Transport = &httpx.RecordingTransport{Dir: *record}
+	case *standin != "":
+		srv := httptest.NewServer(httpx.StandIn{Dir: *standin})
+		defer srv.Close()
+		opts.BaseURL = srv.URL
+	}
+
+	client, err := market.New(*provider, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pair := market.NewPair(*base, *quote)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Свечи берём за целые прошедшие часы, чтобы запрос (и имя записи) не
+	// менялся в пределах часа
+	to := time.Now().UTC().Truncate(time.Hour)
+	for _, cat := range []market.Category{market.Spot, market.Perp} {
+		fmt.Printf("=== %s %s %s ===\n", client.Name(), cat, pair)
+		klines, err := client.Klines(ctx, market.KlineQuery{
+			Category: cat, Pair: pair, Interval: market.Hour1, From: to.Add(-24 * time.Hour), To: to,
+		})
+		report("klines", err, func() {
+			fmt.Printf("  %d свечей", len(klines))
+			if n := len(klines); n > 0 {
+				k := klines[n-1]
+				fmt.Printf(", последняя %s O=%g H=%g L=%g C=%g V=%g", k.OpenTime.Format("02 Jan 15:04"), k.Open, k.High, k.Low, k.Close, k.Volume)
+			}
+			fmt.Println()
+		})
+		t, err := client.Ticker(ctx, cat, pair)
+		report("ticker", err, func() {
+			fmt.Printf("  last=%g 24h: %+.2f%% объём %g (%g %s)\n", t.Last, t.ChangePct, t.Volume, t.QuoteVolume, pair.Quote)
+		})
+		instruments, err := client.Instruments(ctx, cat)
+		report("instruments", err, func() {
+			trading := 0
+			for _, it := range instruments {
+				if it.Trading {
+					trading++
+				}
+			}
+			fmt.Printf("  %d инструментов, торгуются %d\n", len(instruments), trading)
+		})
+	}
+	f, err := client.Funding(ctx, pair)
+	report("funding", err, func() {
+		fmt.Printf("  %.4f%%, следующее списание %s\n", f.Rate*100, f.NextAt.Format("02 Jan 15:04 UTC"))
+	})
+	oi, err := client.OpenInterest(ctx, pair)
+	report("open interest", err, func() {
+		fmt.Printf("  %g %s на %s\n", oi.Amount, pair.Base, oi.At.Format("02 Jan 15:04 UTC"))
+	})
+}
+
+// report печатает результат метода или его ошибку
+func report(method string, err error, ok func()) {
+	fmt.Printf("%s:\n", method)
+	if err != nil {
+		fmt.Printf("  ERROR: %v\n", err)
+		return
+	}
+	ok()
+}
@@ -0,0 +1,63 @@
+// migrate — переносит события из events.json в хранилище из config.yaml
+// (storage.backend / storage.path) вместе с флагами отправки:
+//
+//	go run ./cmd/migrate -from data/events.json
+package main
+
+import (
+	"flag"
+	"log"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/store"
+)
+
+func main() {
+	cfgPath := flag.String("config", "config.yaml", "path to config file")
+	from := flag.String("from", "data/events.json", "JSON-файл с событиями для импорта")
+	flag.Parse()
+
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	if cfg.Storage.Backend == store.BackendJSON && cfg.Storage.Path == *from {
+		log.Fatalf("storage is already %s — nothing to migrate", *from)
+	}
+
+	src, err := store.OpenJSON(*from)
+	if err != nil {
+		log.Fatalf("open source: %v", err)
+	}
+	events, err := src.Query(store.Query{})
+	if err != nil {
+		log.Fatalf("read source: %v", err)
+	}
+
+	dst, err := store.Open(cfg.Storage.Backend, cfg.Storage.Path)
+	if err != nil {
+		log.Fatalf("open %s storage: %v", cfg.Storage.Backend, err)
+	}
+	defer dst.Close()
+
+	// Upsert не трогает флаги уже сохранённых событий — переносим их отдельно,
+	// чтобы повторный запуск не терял отметки об отправке
+	if err := dst.Upsert(events...); err != nil {
+		log.Fatalf("import: %v", err)
+	}
+	for _, e := range events {
+		for flag, set := range map[store.SentFlag]bool{
+			store.SentDigest: e.SentDigest,
+			store.Sent24h:    e.Sent24h,
+			store.Sent2h:     e.Sent2h,
+		} {
+			if !set {
+				continue
+			}
+			if err := dst.MarkSent(e.ID, flag); err != nil {
+				log.Fatalf("mark %s: %v", e.ID, err)
+			}
+		}
+	}
+	log.Printf("Imported %d events from %s into %s (%s)", len(events), *from, cfg.Storage.Path, cfg.Storage.Backend)
+}
@@ -1,88 +1,82 @@
-// scantest — временный инструмент для проверки сканеров
+// scantest — инструмент для проверки сканеров.
+//
+// С -record сырые ответы источников сохраняются в каталог фикстур,
+// с -replay сканеры работают на сохранённых ответах без сети.
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"time"
+	"net/http"
 
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/httpx"
 	"crypto-bot/internal/scanner"
 )
 
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	cfgPath := flag.String("config", "config.yaml", "path to config file (окна и настройки источников)")
+	only := flag.String("source", "", "проверить только один источник (binance, bybit, ...)")
+	record := flag.String("record", "", "сохранить сырые ответы в каталог (напр. internal/scanner/testdata)")
+	replay := flag.String("replay", "", "отдавать ответы из каталога фикстур вместо сети")
+	flag.Parse()
 
-	scanners := map[string]interface {
-		Scan(context.Context) (interface{}, error)
-	}{}
-	_ = scanners
+	var rt http.RoundTripper
+	switch {
+	case *record != "" && *replay != "":
+		log.Fatal("-record and -replay are mutually exclusive")
+	case *record != "":
+		rt = &httpx.RecordingTransport{Dir: *record}
+	case *replay != "":
+		rt = &httpx.ReplayTransport{Dir: *replay}
+	}
 
-	run("Binance", func() {
-		s := scanner.NewBinanceScanner()
-		events, err := s.Scan(ctx)
-		if err != nil {
-			log.Printf("  ERROR: %v", err)
-			return
-		}
-		fmt.Printf("  Найдено %d событий\n", len(events))
-		for _, e := range events {
-			fmt.Printf("  [%s] %s — %s (%s)\n", e.Type, e.Token, e.Title, e.Date.Format("02 Jan 15:04 UTC"))
-		}
-	})
+	// Конфиг не обязателен: без него сканеры работают с настройками по умолчанию
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		log.Printf("config not loaded, using defaults: %v", err)
+		cfg = &config.Config{}
+	}
 
-	run("Bybit", func() {
-		s := scanner.NewBybitScanner()
-		events, err := s.Scan(ctx)
-		if err != nil {
-			log.Printf("  ERROR: %v", err)
-			return
-		}
-		fmt.Printf("  Найдено %d событий\n", len(events))
-		for _, e := range events {
-			fmt.Printf("  [%s] %s — %s (%s)\n", e.Type, e.Token, e.Title, e.Date.Format("02 Jan 15:04 UTC"))
-		}
-	})
+	rules, err := scanner.LoadClassifier(cfg.Scanner.RulesFile)
+	if err != nil {
+		log.Fatalf("load rules: %v", err)
+	}
 
-	run("OKX", func() {
-		s := scanner.NewOKXScanner()
-		events, err := s.Scan(ctx)
-		if err != nil {
-			log.Printf("  ERROR: %v", err)
-			return
-		}
-		fmt.Printf("  Найдено %d событий\n", len(events))
-		for _, e := range events {
-			fmt.Printf("  [%s] %s — %s (%s)\n", e.Type, e.Token, e.Title, e.Date.Format("02 Jan 15:04 UTC"))
-		}
-	})
-
-	run("TokenUnlocks", func() {
-		s := scanner.NewUnlocksScanner()
-		events, err := s.Scan(ctx)
-		if err != nil {
-			log.Printf("  ERROR: %v", err)
-			return
-		}
-		fmt.Printf("  Найдено %d событий\n", len(events))
-		for _, e := range events {
-			fmt.Printf("  [%s] %s — %s (%s)\n", e.Type, e.Token, e.Title, e.Date.Format("02 Jan 15:04 UTC"))
-		}
-	})
-
-	run("Airdrops", func() {
-		s := scanner.NewAirdropsScanner()
-		events, err := s.Scan(ctx)
-		if err != nil {
-			log.Printf("  ERROR: %v", err)
-			return
-		}
-		fmt.Printf("  Найдено %d событий\n", len(events))
-		for _, e := range events {
-			fmt.Printf("  [%s] %s — %s (%s)\n", e.Type, e.Token, e.Title, e.Date.Format("02 Jan 15:04 UTC"))
+	for _, name := range scanner.Names() {
+		if *only != "" && name != *only {
+			continue
 		}
-	})
+		run(name, func() {
+			opts := scanner.NewOptions(cfg.Sources[name])
+			opts.Window = cfg.Windows.ForSource(name)
+			opts.Classifier = rules
+			opts.Transport = rt
+			s, err := scanner.Build(name, opts)
+			if err != nil {
+				log.Printf("  ERROR: %v", err)
+				return
+			}
+			// Как в агрегаторе: у каждого источника свой предел опроса
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.Scanner.ScanTimeout())
+			defer cancel()
+			res, err := s.Scan(ctx)
+			if err != nil {
+				log.Printf("  ERROR: %v", err)
+				return
+			}
+			fmt.Printf("  Получено %d записей, найдено %d событий\n", res.Fetched, len(res.Events))
+			for _, e := range res.Events {
+				fmt.Printf("  [%s] %s — %s (%s)", e.Type, e.Token, e.Title, e.EventAt.Format("02 Jan 15:04 UTC"))
+				if e.Rule != "" {
+					fmt.Printf(" rule=%s", e.Rule)
+				}
+				fmt.Println()
+			}
+		})
+	}
 }
 
 func run(name string, fn func()) {
@@ -2,281 +2,302 @@ package calendar
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
-	"os"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/market"
 	"crypto-bot/internal/model"
 	"crypto-bot/internal/scanner"
+	"crypto-bot/internal/store"
+	"crypto-bot/internal/strategy"
+	"crypto-bot/internal/tokenomics"
 )
 
-// Scanner — интерфейс для всех источников данных
-type Scanner interface {
-	Scan(ctx context.Context) ([]model.Event, error)
+// Aggregator собирает события из всех источников и хранит их в store
+type Aggregator struct {
+	sources []source
+	mu      sync.Mutex
+	store   store.Store
+	archive store.Store // прошедшие события, перенесённые из store
+	// retention — сколько хранить события в архиве; 0 — без ограничения
+	retention time.Duration
+	status    map[string]*SourceStatus // source name → health
+	// scanTimeout — предел опроса одного источника за обновление
+	scanTimeout time.Duration
+	windows     config.WindowsConfig
+	unlocks     config.UnlocksConfig
+	merge       mergePolicy
+	updates     []Update // существенные изменения событий, ещё не отправленные
+	outcomes    outcomeTracker
+	// tokenomics — провайдер токеномики (с кэшем); nil — обогащение выключено
+	tokenomics tokenomics.Provider
+	enriching  sync.Mutex // занят, пока идёт EnrichTokenomics
+	// strategies — стратегии из strategies.file, перечитываются при изменении
+	strategies *strategy.Watcher
 }
 
-// Aggregator собирает события из всех источников и хранит кэш в events.json
-type Aggregator struct {
-	scanners  []Scanner
-	cachePath string
-	mu        sync.Mutex
-	cache     map[string]model.Event // id → event
+// scanResult — итог опроса одного источника
+type scanResult struct {
+	name string
+	res  scanner.Result
+	err  error
 }
 
-func NewAggregator(cachePath string, sources struct {
-	Binance      bool
-	Bybit        bool
-	OKX          bool
-	TokenUnlocks bool
-	Airdrops     bool
-}) *Aggregator {
-	a := &Aggregator{
-		cachePath: cachePath,
-		cache:     make(map[string]model.Event),
-	}
+// source — сканер вместе с именем, под которым он зарегистрирован
+type source struct {
+	name    string
+	scanner scanner.Scanner
+}
 
-	if sources.Binance {
-		a.scanners = append(a.scanners, scanner.NewBinanceScanner())
+// NewAggregator строит сканеры всех включённых источников через реестр
+// пакета scanner. Неизвестные имена в конфиге пропускаются с предупреждением.
+// Ошибка — если не загрузились правила классификации анонсов или стратегии
+// или не открылось хранилище событий.
+func NewAggregator(cfg *config.Config) (*Aggregator, error) {
+	rules, err := scanner.LoadClassifier(cfg.Scanner.RulesFile)
+	if err != nil {
+		return nil, err
+	}
+	// Фильтр days_to_unlock дальше окна разлоков никогда не увидел бы разлок
+	strategies, err := strategy.NewWatcher(cfg.Strategies.File, strategy.Limits{
+		strategy.MetricDaysToUnlock: cfg.Unlocks.ScanHorizon(cfg.Windows).Hours() / 24,
+	})
+	if err != nil {
+		return nil, err
+	}
+	prices, err := market.New(cfg.Market.Provider, market.Options{BaseURL: cfg.Market.BaseURL, RPS: cfg.Market.RPS})
+	if err != nil {
+		return nil, err
 	}
-	if sources.Bybit {
-		a.scanners = append(a.scanners, scanner.NewBybitScanner())
+	supply, err := tokenomics.New(cfg.Tokenomics.Provider, tokenomics.Options{
+		BaseURL: cfg.Tokenomics.BaseURL,
+		APIKey:  cfg.Tokenomics.APIKey,
+		File:    cfg.Tokenomics.File,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if supply != nil {
+		supply = tokenomics.NewCache(supply, cfg.Tokenomics.TTL())
+	}
+	st, err := store.Open(cfg.Storage.Backend, cfg.Storage.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	archive, err := store.Open(cfg.Storage.Backend, cfg.Storage.ArchivePath)
+	if err != nil {
+		st.Close()
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+
+	a := &Aggregator{
+		store:       st,
+		archive:     archive,
+		retention:   time.Duration(cfg.Storage.RetentionDays) * 24 * time.Hour,
+		status:      make(map[string]*SourceStatus),
+		scanTimeout: cfg.Scanner.ScanTimeout(),
+		windows:     cfg.Windows,
+		unlocks:     cfg.Unlocks,
+		merge:       newMergePolicy(cfg.Merge),
+		outcomes:    outcomeTracker{client: prices, quote: cfg.Market.Quote},
+		tokenomics:  supply,
+		strategies:  strategies,
 	}
-	if sources.OKX {
-		a.scanners = append(a.scanners, scanner.NewOKXScanner())
+
+	sources := cfg.Sources
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
 	}
-	if sources.TokenUnlocks {
-		a.scanners = append(a.scanners, scanner.NewUnlocksScanner())
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !sources[name].Enabled {
+			continue
+		}
+		opts := scanner.NewOptions(sources[name])
+		opts.Window = cfg.Windows.ForSource(name)
+		opts.Classifier = rules
+		s, err := scanner.Build(name, opts)
+		if err != nil {
+			log.Printf("[aggregator] skip source: %v (known: %s)", err, strings.Join(scanner.Names(), ", "))
+			continue
+		}
+		a.sources = append(a.sources, source{name: name, scanner: s})
+		a.status[name] = &SourceStatus{Name: name}
 	}
-	if sources.Airdrops {
-		a.scanners = append(a.scanners, scanner.NewAirdropsScanner())
+	return a, nil
+}
+
+// StorageRecovered сообщает, что хранилище было повреждено и при старте
+// восстановлено из резервной копии (часть событий и флагов могла потеряться).
+func (a *Aggregator) StorageRecovered() error {
+	if r, ok := a.store.(store.Recoverer); ok {
+		return r.Recovered()
 	}
+	return nil
+}
 
-	// Загружаем кэш с диска при старте
-	a.loadCache()
-	return a
+// Close закрывает хранилище событий и архив
+func (a *Aggregator) Close() error {
+	return errors.Join(a.store.Close(), a.archive.Close())
 }
 
 // Refresh опрашивает все источники, обновляет кэш, возвращает список всех событий
 func (a *Aggregator) Refresh(ctx context.Context) []model.Event {
 	// Параллельный сбор со всех источников
-	type result struct {
-		events []model.Event
-	}
-	ch := make(chan result, len(a.scanners))
+	ch := make(chan scanResult, len(a.sources))
 
-	for _, s := range a.scanners {
-		s := s
+	for _, src := range a.sources {
+		src := src
 		go func() {
-			scanCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+			scanCtx, cancel := context.WithTimeout(ctx, a.scanTimeout)
 			defer cancel()
-			evs, err := s.Scan(scanCtx)
+			res, err := src.scanner.Scan(scanCtx)
 			if err != nil {
-				log.Printf("[aggregator] scanner error: %v", err)
-				evs = nil
+				log.Printf("[aggregator] %s scanner error: %v", src.name, err)
 			}
-			ch <- result{events: evs}
+			ch <- scanResult{name: src.name, res: res, err: err}
 		}()
 	}
 
-	var fresh []model.Event
-	for range a.scanners {
+	var (
+		fresh   []model.Event
+		results []scanResult
+	)
+	for range a.sources {
 		r := <-ch
-		fresh = append(fresh, r.events...)
+		results = append(results, r)
+		fresh = append(fresh, r.res.Events...)
 	}
 
-	fresh = deduplicateCrossSource(fresh)
-
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	// Добавляем новые события, сохраняем флаги отправки для существующих
-	for _, e := range fresh {
-		if existing, ok := a.cache[e.ID]; ok {
-			// Обновляем данные, но сохраняем флаги отправки
-			e.SentDigest = existing.SentDigest
-			e.Sent24h = existing.Sent24h
-			e.Sent2h = existing.Sent2h
-		}
-		a.cache[e.ID] = e
+	// Обновляем здоровье источников: kept — сколько событий источника
+	// стали основными в сводных событиях после слияния дублей
+	kept := make(map[string]int)
+	for _, e := range a.merge.merge(fresh) {
+		kept[e.Source]++
 	}
-
-	// Удаляем устаревшие записи из других источников для тех же событий.
-	// После deduplicateCrossSource в fresh — только winners. Все записи кэша
-	// с тем же TOKEN/DATE/TYPE но другим source — проигравшие, удаляем их.
-	for _, winner := range fresh {
-		winDate := winner.Date.UTC().Format("20060102")
-		for id, cached := range a.cache {
-			if id == winner.ID {
-				continue
-			}
-			if cached.Token == winner.Token &&
-				cached.Date.UTC().Format("20060102") == winDate &&
-				cached.Type == winner.Type {
-				delete(a.cache, id)
-			}
-		}
+	now := time.Now().UTC()
+	for _, r := range results {
+		a.status[r.name].record(now, r.res, r.err, kept[r.name])
 	}
 
-	// Чистим старые события (старше 2 дней)
-	cutoff := time.Now().UTC().Add(-48 * time.Hour)
-	for id, e := range a.cache {
-		if e.Date.Before(cutoff) {
-			delete(a.cache, id)
-		}
+	// Сверяем с сохранёнными версиями: журнал изменений, переносы дат, пропажи
+	fresh, missing, replaced := a.trackChanges(fresh, results, now)
+
+	// Каждый источник хранится отдельно — дубли сливаются только при выдаче.
+	// Флаги отправки существующих событий store сохраняет сам; заменяемые
+	// версии удаляются той же операцией
+	if err := a.store.Replace(replaced, slices.Concat(fresh, missing)...); err != nil {
+		log.Printf("[aggregator] failed to save events: %v", err)
 	}
 
-	a.saveCache()
-	return a.allEvents()
+	// Переносим старые события в архив (по умолчанию старше 2 дней)
+	a.archivePast(now.Add(-a.windows.PruneAfter()))
+
+	return a.rankUnlocks(a.merge.merge(a.allEvents()))
+}
+
+// Windows возвращает политику окон, с которой построен агрегатор.
+func (a *Aggregator) Windows() config.WindowsConfig {
+	return a.windows
 }
 
 // Events возвращает текущий кэш без запроса источников
 func (a *Aggregator) Events() []model.Event {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	return deduplicateCrossSource(a.allEvents())
+	return a.rankUnlocks(a.merge.merge(a.allEvents()))
 }
 
 // MarkSentDigest помечает событие как отправленное в дайджест
-func (a *Aggregator) MarkSentDigest(id string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if e, ok := a.cache[id]; ok {
-		e.SentDigest = true
-		a.cache[id] = e
-	}
-	a.saveCache()
+func (a *Aggregator) MarkSentDigest(e model.Event) {
+	a.markSent(e, store.SentDigest)
 }
 
 // MarkSent24h помечает событие как отправленное (алерт 24ч)
-func (a *Aggregator) MarkSent24h(id string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if e, ok := a.cache[id]; ok {
-		e.Sent24h = true
-		a.cache[id] = e
-	}
-	a.saveCache()
+func (a *Aggregator) MarkSent24h(e model.Event) {
+	a.markSent(e, store.Sent24h)
 }
 
 // MarkSent2h помечает событие как отправленное (алерт 2ч)
-func (a *Aggregator) MarkSent2h(id string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	if e, ok := a.cache[id]; ok {
-		e.Sent2h = true
-		a.cache[id] = e
-	}
-	a.saveCache()
+func (a *Aggregator) MarkSent2h(e model.Event) {
+	a.markSent(e, store.Sent2h)
 }
 
-// sourcePriority returns a lower number for higher-priority sources.
-func sourcePriority(source string) int {
-	switch source {
-	case "binance":
-		return 1
-	case "bybit":
-		return 2
-	case "okx":
-		return 3
-	case "tokenunlocks":
-		return 4
-	case "airdrops":
-		return 5
-	}
-	return 99
-}
-
-// deduplicateCrossSource removes duplicate events from different sources.
-// When the same TOKEN+DATE+TYPE appears from multiple sources, the highest-priority
-// source wins. Sent flags are preserved from whichever entry had them set.
-func deduplicateCrossSource(events []model.Event) []model.Event {
-	type key struct {
-		token string
-		date  string
-		eType model.EventType
-	}
-
-	// Group events by cross-source key
-	groups := make(map[key][]model.Event)
-	var order []key // preserve insertion order for deterministic output
-	for _, e := range events {
-		k := key{
-			token: e.Token,
-			date:  e.Date.UTC().Format("20060102"),
-			eType: e.Type,
-		}
-		if _, exists := groups[k]; !exists {
-			order = append(order, k)
+// markSent выставляет флаг у события и всех его площадок — иначе площадка
+// без флага снова попала бы в алерт
+func (a *Aggregator) markSent(e model.Event, flag store.SentFlag) {
+	for _, id := range e.IDs() {
+		if err := a.store.MarkSent(id, flag); err != nil {
+			log.Printf("[aggregator] mark %s sent: %v", id, err)
 		}
-		groups[k] = append(groups[k], e)
 	}
-
-	out := make([]model.Event, 0, len(order))
-	for _, k := range order {
-		group := groups[k]
-		if len(group) == 1 {
-			out = append(out, group[0])
-			continue
-		}
-		// Pick winner: lowest priority number = highest priority
-		winner := group[0]
-		for _, e := range group[1:] {
-			if sourcePriority(e.Source) < sourcePriority(winner.Source) {
-				winner = e
-			}
-		}
-		// Merge sent flags from all group members
-		for _, e := range group {
-			if e.SentDigest {
-				winner.SentDigest = true
-			}
-			if e.Sent24h {
-				winner.Sent24h = true
-			}
-			if e.Sent2h {
-				winner.Sent2h = true
-			}
-		}
-		out = append(out, winner)
-	}
-	return out
 }
 
 func (a *Aggregator) allEvents() []model.Event {
-	out := make([]model.Event, 0, len(a.cache))
-	for _, e := range a.cache {
-		out = append(out, e)
+	events, err := a.store.Query(store.Query{})
+	if err != nil {
+		log.Printf("[aggregator] failed to load events: %v", err)
 	}
-	return out
+	return events
 }
 
-func (a *Aggregator) loadCache() {
-	data, err := os.ReadFile(a.cachePath)
+// archivePast переносит события раньше cutoff в архив — с итоговым состоянием
+// и флагами отправки — и чистит архив старше retention. Из основного
+// хранилища событие удаляется, только если оно сохранено в архиве.
+func (a *Aggregator) archivePast(cutoff time.Time) {
+	past, err := a.store.Query(store.Query{To: cutoff})
 	if err != nil {
-		// Файл может не существовать при первом запуске
+		log.Printf("[aggregator] query past events: %v", err)
 		return
 	}
-	var events []model.Event
-	if err := json.Unmarshal(data, &events); err != nil {
-		log.Printf("[aggregator] failed to parse cache: %v", err)
-		return
+	if len(past) > 0 {
+		if err := a.archive.Upsert(past...); err != nil {
+			log.Printf("[aggregator] failed to archive events: %v", err)
+			return
+		}
+		if _, err := a.store.Prune(cutoff); err != nil {
+			log.Printf("[aggregator] failed to prune events: %v", err)
+		}
+		log.Printf("[aggregator] archived %d past events", len(past))
 	}
-	for _, e := range events {
-		a.cache[e.ID] = e
+	if a.retention > 0 {
+		if _, err := a.archive.Prune(cutoff.Add(-a.retention)); err != nil {
+			log.Printf("[aggregator] failed to prune archive: %v", err)
+		}
 	}
-	log.Printf("[aggregator] loaded %d events from cache", len(a.cache))
 }
 
-func (a *Aggregator) saveCache() {
-	events := a.allEvents()
-	data, err := json.MarshalIndent(events, "", "  ")
+// History возвращает прошедшие события (архив и ещё не перенесённые)
+// в диапазоне [from, to), подходящие под q, в сводном виде по дате.
+// Пустой q.Token — все тикеры.
+func (a *Aggregator) History(q store.Query) []model.Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now().UTC()
+	if q.To.IsZero() || q.To.After(now) {
+		q.To = now
+	}
+	archived, err := a.archive.Query(q)
 	if err != nil {
-		log.Printf("[aggregator] failed to marshal cache: %v", err)
-		return
+		log.Printf("[aggregator] query archive: %v", err)
 	}
-	if err := os.WriteFile(a.cachePath, data, 0644); err != nil {
-		log.Printf("[aggregator] failed to save cache: %v", err)
+	recent, err := a.store.Query(q)
+	if err != nil {
+		log.Printf("[aggregator] query history: %v", err)
 	}
+	events := a.rankUnlocks(a.merge.merge(slices.Concat(archived, recent)))
+	sort.Slice(events, func(i, j int) bool { return events[i].EventAt.Before(events[j].EventAt) })
+	return events
 }
@@ -0,0 +1,199 @@
+package calendar
+
+import (
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/scanner"
+	"crypto-bot/internal/store"
+)
+
+// missingAfterScans — после скольких успешных опросов без события считать,
+// что источник его убрал (отмена). Один пропуск бывает из-за пагинации.
+const missingAfterScans = 2
+
+// Update — существенное изменение события для уведомления "ОБНОВЛЕНО"
+type Update struct {
+	Event   model.Event
+	Changes []model.Change // изменения, замеченные в этом обновлении
+}
+
+// trackChanges сравнивает свежие события с сохранёнными, дописывает журнал
+// изменений и копит существенные изменения для TakeUpdates. Свежая версия
+// заменяет сохранённую, если у той другой ID (запись старого формата) или
+// перенесена дата; флаги отправки переносятся, кроме алертов 24ч/2ч при
+// переносе даты. Вызывается под a.mu; возвращает fresh с историей,
+// события, пропавшие из источников, и ID заменяемых версий — их удаляют
+// вместе с записью fresh (store.Replace), чтобы сбой между удалением и
+// записью не потерял событие.
+func (a *Aggregator) trackChanges(fresh []model.Event, results []scanResult, now time.Time) (events, missing []model.Event, replacedIDs []string) {
+	replaced := make(map[string]bool) // ID сохранённых версий, заменяемых свежими
+	for i, e := range fresh {
+		old, ok := a.previous(e)
+		if !ok {
+			if e.Rule != "" {
+				log.Printf("[aggregator] new %s event %s %s (rule %s)", e.Type, e.Source, e.Token, e.Rule)
+			}
+			continue
+		}
+		// Время из текста анонса сканер помнит только в памяти процесса: после
+		// перезапуска тот же анонс может прийти лишь с датой. Точное время
+		// сохранённой версии в тот же день не затираем
+		if e.Confidence() == model.DateOnly && old.Confidence() == model.DateExact &&
+			e.EventAt.UTC().Format(time.DateOnly) == old.EventAt.UTC().Format(time.DateOnly) {
+			e.EventAt, e.DateConfidence = old.EventAt, model.DateExact
+		}
+		changes := diffEvent(old, e, now)
+		e.Changes = slices.Concat(old.Changes, changes)
+		// Токеномику дописывает EnrichTokenomics — до неё остаётся прежняя
+		if e.Tokenomics == nil {
+			e.Tokenomics = old.Tokenomics
+		}
+		e.SentDigest = old.SentDigest
+		e.Sent24h, e.Sent2h = old.Sent24h, old.Sent2h
+		if old.ID != e.ID {
+			replaced[old.ID] = true
+		}
+		if hasSignificant(changes) {
+			log.Printf("[aggregator] %s changed: %v", e.ID, changes)
+			// Дата изменилась — алерты по старой дате уже не актуальны
+			e.Sent24h, e.Sent2h = false, false
+			replaced[old.ID] = true
+			a.updates = append(a.updates, Update{Event: e, Changes: changes})
+		}
+		fresh[i] = e
+	}
+	for id := range replaced {
+		replacedIDs = append(replacedIDs, id)
+	}
+
+	// Будущие события источника, которых он больше не возвращает
+	upcoming, err := a.store.Query(store.Query{From: now})
+	if err != nil {
+		log.Printf("[aggregator] query upcoming: %v", err)
+		return fresh, nil, replacedIDs
+	}
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+		seen := make(map[string]bool, len(r.res.Events))
+		for _, e := range r.res.Events {
+			seen[e.ID] = true
+		}
+		// Ленту анонсов сканер листает только до начала окна по дате
+		// публикации: анонс старше него не пропал, а просто не просмотрен
+		scanned, _ := a.windows.ForSource(r.name).Range(now)
+		for _, e := range upcoming {
+			if e.Source != r.name || seen[e.ID] || replaced[e.ID] ||
+				!a.windows.For(e.Source, string(e.Type)).Contains(now, e.EventAt) {
+				continue
+			}
+			if !e.AnnouncedAt.IsZero() && !e.AnnouncedAt.After(scanned) {
+				continue
+			}
+			e.MissedScans++
+			if e.MissedScans == missingAfterScans {
+				ch := model.Change{Field: model.FieldStatus, Old: model.StatusActive, New: model.StatusMissing, SeenAt: now}
+				e.Changes = append(e.Changes, ch)
+				log.Printf("[aggregator] %s disappeared from %s", e.ID, r.name)
+				a.updates = append(a.updates, Update{Event: e, Changes: []model.Change{ch}})
+			}
+			missing = append(missing, e)
+		}
+	}
+	return fresh, missing, replacedIDs
+}
+
+// previous находит сохранённую версию события: по ID, затем по ID старого
+// формата (source:TOKEN:YYYYMMDD — так кэш переходит на ID от анонсов), затем
+// по анонсу, из которого событие получено.
+func (a *Aggregator) previous(e model.Event) (model.Event, bool) {
+	if old, ok, err := a.store.Get(e.ID); err == nil && ok {
+		return old, true
+	}
+	if e.GroupID == "" {
+		return model.Event{}, false
+	}
+	if old, ok, err := a.store.Get(scanner.LegacyEventID(e)); err == nil && ok {
+		return old, true
+	}
+	same, err := a.store.Query(store.Query{Types: []model.EventType{e.Type}, Token: e.Token})
+	if err != nil {
+		return model.Event{}, false
+	}
+	for _, old := range same {
+		if old.Source == e.Source && old.GroupID == e.GroupID {
+			return old, true
+		}
+	}
+	return model.Event{}, false
+}
+
+// diffEvent возвращает изменения полей между сохранённой и свежей версией
+func diffEvent(old, e model.Event, now time.Time) []model.Change {
+	var out []model.Change
+	add := func(field, before, after string) {
+		if before != after {
+			out = append(out, model.Change{Field: field, Old: before, New: after, SeenAt: now})
+		}
+	}
+	add(model.FieldDate, fmtChangeDate(old), fmtChangeDate(e))
+	add(model.FieldTitle, old.Title, e.Title)
+	add(model.FieldDetails, old.Details, e.Details)
+	if old.MissedScans >= missingAfterScans {
+		add(model.FieldStatus, model.StatusMissing, model.StatusActive)
+	}
+	return out
+}
+
+// dateTBD — дата события в журнале, пока она не известна (взята из анонса)
+const dateTBD = "TBD"
+
+// fmtChangeDate — дата события для журнала; время — только если оно известно
+func fmtChangeDate(e model.Event) string {
+	switch e.Confidence() {
+	case model.DateExact:
+		return e.EventAt.UTC().Format("2006-01-02 15:04")
+	case model.DateInferred:
+		return dateTBD
+	}
+	return e.EventAt.UTC().Format("2006-01-02")
+}
+
+// hasSignificant сообщает, есть ли среди изменений перенос даты или
+// пропажа события. Появление даты у события "TBD" и уточнение времени в тот
+// же день ("2026-02-21" → "2026-02-21 10:00") переносом не считаются.
+func hasSignificant(changes []model.Change) bool {
+	for _, c := range changes {
+		switch c.Field {
+		case model.FieldStatus:
+			if c.New == model.StatusMissing {
+				return true
+			}
+		case model.FieldDate:
+			if c.Old == dateTBD || c.New == dateTBD {
+				continue
+			}
+			if len(c.Old) != len(c.New) && c.Old[:10] == c.New[:10] {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// TakeUpdates возвращает накопленные существенные изменения событий и
+// очищает список — каждое изменение уходит в уведомление один раз.
+func (a *Aggregator) TakeUpdates() []Update {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := a.updates
+	a.updates = nil
+	sort.Slice(out, func(i, j int) bool { return out[i].Event.EventAt.Before(out[j].Event.EventAt) })
+	return out
+}
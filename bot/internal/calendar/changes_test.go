@@ -0,0 +1,178 @@
+package calendar
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/scanner"
+	"crypto-bot/internal/store"
+)
+
+func testAggregator(t *testing.T, stored ...model.Event) *Aggregator {
+	t.Helper()
+	st, err := store.OpenJSON(filepath.Join(t.TempDir(), "events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Upsert(stored...); err != nil {
+		t.Fatal(err)
+	}
+	return &Aggregator{store: st, windows: config.WindowsConfig{}}
+}
+
+// refreshWith повторяет запись из Refresh: trackChanges и store.Replace
+func refreshWith(t *testing.T, a *Aggregator, now time.Time, results ...scanResult) (missing []model.Event) {
+	t.Helper()
+	var fresh []model.Event
+	for _, r := range results {
+		fresh = append(fresh, r.res.Events...)
+	}
+	fresh, missing, replaced := a.trackChanges(fresh, results, now)
+	if err := a.store.Replace(replaced, slices.Concat(fresh, missing)...); err != nil {
+		t.Fatal(err)
+	}
+	return missing
+}
+
+func TestTrackChangesMissingOnlyWithinLookback(t *testing.T) {
+	now := utc("2026-03-03T12:00:00Z")
+	day := 24 * time.Hour
+	a := testAggregator(t,
+		model.Event{ID: "binance:recent:KITE", Source: "binance", Token: "KITE", Type: model.EventListing,
+			EventAt: now.Add(2 * day), AnnouncedAt: now.Add(-3 * day)},
+		// Анонс старше окна (14 дней): сканер до него не долистывает
+		model.Event{ID: "binance:old:ZKJ", Source: "binance", Token: "ZKJ", Type: model.EventListing,
+			EventAt: now.Add(3 * day), AnnouncedAt: now.Add(-20 * day)},
+		// Без даты анонса (разлоки) — судим только по дате события
+		model.Event{ID: "tokenunlocks:ARB:20260306", Source: "tokenunlocks", Token: "ARB", Type: model.EventUnlock,
+			EventAt: now.Add(3 * day)},
+		// Источник не ответил — его события не пропадают
+		model.Event{ID: "bybit:a:BIRB", Source: "bybit", Token: "BIRB", Type: model.EventListing,
+			EventAt: now.Add(day), AnnouncedAt: now.Add(-day)},
+	)
+	results := []scanResult{
+		{name: "binance", res: scanner.Result{}},
+		{name: "tokenunlocks", res: scanner.Result{}},
+		{name: "bybit", err: errors.New("timeout")},
+	}
+
+	var ids []string
+	for _, e := range refreshWith(t, a, now, results...) {
+		ids = append(ids, e.ID)
+	}
+	slices.Sort(ids)
+	if want := []string{"binance:recent:KITE", "tokenunlocks:ARB:20260306"}; !slices.Equal(ids, want) {
+		t.Errorf("missing = %v, want %v", ids, want)
+	}
+	if len(a.updates) != 0 {
+		t.Errorf("updates after one miss: %d", len(a.updates))
+	}
+
+	refreshWith(t, a, now.Add(time.Hour), results...)
+	var gone []string
+	for _, u := range a.updates {
+		gone = append(gone, u.Event.ID)
+		if last := u.Changes[len(u.Changes)-1]; last.Field != model.FieldStatus || last.New != model.StatusMissing {
+			t.Errorf("%s: change %+v", u.Event.ID, last)
+		}
+	}
+	slices.Sort(gone)
+	if !slices.Equal(gone, ids) {
+		t.Errorf("disappeared = %v, want %v", gone, ids)
+	}
+	if e, _, _ := a.store.Get("binance:old:ZKJ"); e.MissedScans != 0 {
+		t.Errorf("old announcement missed %d scans", e.MissedScans)
+	}
+}
+
+func TestTrackChangesReplacesVersions(t *testing.T) {
+	now := utc("2026-03-03T12:00:00Z")
+	a := testAggregator(t,
+		model.Event{ID: "binance:KITE:20260305", Source: "binance", Token: "KITE", Type: model.EventListing,
+			GroupID: "binance:a", Title: "Binance Will List KITE", EventAt: utc("2026-03-05T10:00:00Z"),
+			AnnouncedAt: utc("2026-03-02T09:00:00Z")},
+		model.Event{ID: "okx:b:ZKJ", Source: "okx", Token: "ZKJ", Type: model.EventListing,
+			Title: "OKX to list ZKJ", EventAt: utc("2026-03-06T08:00:00Z"), AnnouncedAt: utc("2026-03-01T12:00:00Z")},
+	)
+	for _, id := range []string{"binance:KITE:20260305", "okx:b:ZKJ"} {
+		for _, f := range []store.SentFlag{store.SentDigest, store.Sent24h} {
+			if err := a.store.MarkSent(id, f); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Binance: тот же анонс под ID нового формата, дата та же.
+	// OKX: тот же ID, дата перенесена на день.
+	rekeyed := model.Event{ID: "binance:a:KITE", Source: "binance", Token: "KITE", Type: model.EventListing,
+		GroupID: "binance:a", Title: "Binance Will List KITE", EventAt: utc("2026-03-05T10:00:00Z"),
+		AnnouncedAt: utc("2026-03-02T09:00:00Z")}
+	moved := model.Event{ID: "okx:b:ZKJ", Source: "okx", Token: "ZKJ", Type: model.EventListing,
+		Title: "OKX to list ZKJ", EventAt: utc("2026-03-07T08:00:00Z"), AnnouncedAt: utc("2026-03-01T12:00:00Z")}
+	missing := refreshWith(t, a, now,
+		scanResult{name: "binance", res: scanner.Result{Events: []model.Event{rekeyed}}},
+		scanResult{name: "okx", res: scanner.Result{Events: []model.Event{moved}}},
+	)
+	if len(missing) != 0 {
+		t.Errorf("replaced versions counted as missing: %v", missing)
+	}
+
+	all, _ := a.store.Query(store.Query{})
+	if len(all) != 2 {
+		t.Fatalf("stored %d events, want 2", len(all))
+	}
+	if _, ok, _ := a.store.Get("binance:KITE:20260305"); ok {
+		t.Error("old ID still stored")
+	}
+	// Смена ID без переноса — все флаги на месте
+	if e, _, _ := a.store.Get(rekeyed.ID); !e.SentDigest || !e.Sent24h || len(e.Changes) != 0 {
+		t.Errorf("rekeyed: digest %v, 24h %v, changes %v", e.SentDigest, e.Sent24h, e.Changes)
+	}
+	// Перенос даты — алерты по старой дате сброшены, дайджест — нет
+	e, _, _ := a.store.Get(moved.ID)
+	if !e.SentDigest || e.Sent24h || !e.EventAt.Equal(moved.EventAt) {
+		t.Errorf("moved: digest %v, 24h %v, at %v", e.SentDigest, e.Sent24h, e.EventAt)
+	}
+	if len(e.Changes) != 1 || e.Changes[0].Field != model.FieldDate {
+		t.Errorf("moved changes = %+v", e.Changes)
+	}
+	if len(a.updates) != 1 || a.updates[0].Event.ID != moved.ID {
+		t.Errorf("updates = %+v", a.updates)
+	}
+}
+
+func TestTrackChangesKeepsExactTimeAfterRestart(t *testing.T) {
+	now := utc("2026-03-03T12:00:00Z")
+	exact := model.Event{ID: "binance:a:KITE", Source: "binance", Token: "KITE", Type: model.EventListing,
+		GroupID: "binance:a", EventAt: utc("2026-03-05T10:00:00Z"), DateConfidence: model.DateExact,
+		AnnouncedAt: utc("2026-03-02T09:00:00Z")}
+	a := testAggregator(t, exact)
+	if err := a.store.MarkSent(exact.ID, store.Sent24h); err != nil {
+		t.Fatal(err)
+	}
+
+	// Кэш страниц анонсов пуст — сканер вернул тот же анонс только с датой
+	dateOnly := exact
+	dateOnly.EventAt, dateOnly.DateConfidence = utc("2026-03-05T00:00:00Z"), model.DateOnly
+	refreshWith(t, a, now, scanResult{name: "binance", res: scanner.Result{Events: []model.Event{dateOnly}}})
+
+	e, _, _ := a.store.Get(exact.ID)
+	if !e.EventAt.Equal(exact.EventAt) || e.Confidence() != model.DateExact {
+		t.Errorf("event at %v (%s), want %v exact", e.EventAt, e.Confidence(), exact.EventAt)
+	}
+	if len(e.Changes) != 0 || !e.Sent24h || len(a.updates) != 0 {
+		t.Errorf("changes %v, 24h %v, updates %d", e.Changes, e.Sent24h, len(a.updates))
+	}
+
+	// Дата в анонсе сменилась — это перенос, а не потеря времени
+	dateOnly.EventAt = utc("2026-03-06T00:00:00Z")
+	refreshWith(t, a, now, scanResult{name: "binance", res: scanner.Result{Events: []model.Event{dateOnly}}})
+	if e, _, _ := a.store.Get(exact.ID); !e.EventAt.Equal(dateOnly.EventAt) || len(a.updates) != 1 {
+		t.Errorf("moved: at %v, updates %d", e.EventAt, len(a.updates))
+	}
+}
@@ -0,0 +1,113 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
+	"crypto-bot/internal/tokenomics"
+)
+
+// enrichTimeout — сколько один вызов EnrichTokenomics тратит на поиск.
+// Токены, до которых не дошла очередь, сохраняют прежние данные и
+// дополнятся при следующем вызове.
+const enrichTimeout = 2 * time.Minute
+
+// EnrichTokenomics дописывает токеномику предстоящим событиям кэша; ближайшие
+// по времени — первыми. Провайдер может отвечать минутами, поэтому Refresh
+// её не ждёт: бот вызывает EnrichTokenomics в фоне после обновления, дайджест —
+// перед сборкой. Параллельный вызов сразу возвращает 0. Возвращает, сколько
+// событий обновлено.
+func (a *Aggregator) EnrichTokenomics(ctx context.Context) int {
+	if a.tokenomics == nil || !a.enriching.TryLock() {
+		return 0
+	}
+	defer a.enriching.Unlock()
+
+	a.mu.Lock()
+	events, err := a.store.Query(store.Query{From: time.Now().UTC()})
+	a.mu.Unlock()
+	if err != nil {
+		log.Printf("[aggregator] tokenomics: query upcoming: %v", err)
+		return 0
+	}
+	found := a.lookupTokenomics(ctx, events)
+
+	// Пока шёл поиск, Refresh мог обновить события — пишем поверх свежих версий
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var updated []model.Event
+	for _, e := range events {
+		t := found[e.Token]
+		if t == nil {
+			continue // нет данных — прежняя токеномика остаётся
+		}
+		cur, ok, err := a.store.Get(e.ID)
+		if err != nil || !ok {
+			continue // заменено или ушло в архив
+		}
+		cur.Tokenomics = t
+		updated = append(updated, cur)
+	}
+	if len(updated) == 0 {
+		return 0
+	}
+	if err := a.store.Upsert(updated...); err != nil {
+		log.Printf("[aggregator] tokenomics: save: %v", err)
+		return 0
+	}
+	return len(updated)
+}
+
+// lookupTokenomics ищет токеномику тикеров событий, каждый — один раз;
+// события без тикера пропускаются. Возвращает тикер → токеномика, nil —
+// данных нет.
+func (a *Aggregator) lookupTokenomics(ctx context.Context, events []model.Event) map[string]*model.Tokenomics {
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+
+	now := time.Now()
+	order := make([]model.Event, len(events))
+	copy(order, events)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].EventAt.Sub(now).Abs() < order[j].EventAt.Sub(now).Abs()
+	})
+
+	found := make(map[string]*model.Tokenomics)
+	failed := 0
+	for _, e := range order {
+		token := e.Token
+		if token == "" || token == model.UnknownToken {
+			continue
+		}
+		if _, seen := found[token]; seen {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		v, err := a.tokenomics.Lookup(ctx, token)
+		switch {
+		case err == nil:
+			found[token] = &v
+		case errors.Is(err, tokenomics.ErrNotFound):
+			found[token] = nil
+		default:
+			if ctx.Err() == nil {
+				log.Printf("[aggregator] tokenomics %s: %v", token, err)
+			}
+			found[token] = nil
+			failed++
+		}
+	}
+	if ctx.Err() != nil {
+		log.Printf("[aggregator] tokenomics: out of time after %d tokens", len(found))
+	} else if failed > 0 {
+		log.Printf("[aggregator] tokenomics: %d of %d tokens failed", failed, len(found))
+	}
+	return found
+}
@@ -0,0 +1,83 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/tokenomics"
+)
+
+// fakeSupply отвечает токеномикой из known и запоминает вопросы
+type fakeSupply struct {
+	known map[string]float64 // тикер → MCap
+	asked []string
+}
+
+func (f *fakeSupply) Lookup(ctx context.Context, token string) (model.Tokenomics, error) {
+	f.asked = append(f.asked, token)
+	switch mcap, ok := f.known[token]; {
+	case ok:
+		return model.Tokenomics{MarketCap: mcap}, nil
+	case token == "FAIL":
+		return model.Tokenomics{}, errors.New("status 500")
+	}
+	return model.Tokenomics{}, fmt.Errorf("fake %s: %w", token, tokenomics.ErrNotFound)
+}
+
+func TestEnrichTokenomics(t *testing.T) {
+	now := time.Now().UTC()
+	old := &model.Tokenomics{MarketCap: 1}
+	a := testAggregator(t,
+		model.Event{ID: "binance:a:KITE", Source: "binance", Token: "KITE", EventAt: now.Add(48 * time.Hour)},
+		model.Event{ID: "bybit:b:KITE", Source: "bybit", Token: "KITE", EventAt: now.Add(50 * time.Hour)},
+		model.Event{ID: "okx:c:ZKJ", Source: "okx", Token: "ZKJ", EventAt: now.Add(24 * time.Hour)},
+		model.Event{ID: "airdrops:d", Source: "airdrops", Token: model.UnknownToken, EventAt: now.Add(time.Hour)},
+		model.Event{ID: "binance:e", Source: "binance", Token: "", EventAt: now.Add(time.Hour)},
+		// Провайдер не знает или не ответил — прежние данные остаются
+		model.Event{ID: "okx:f:NEW", Source: "okx", Token: "NEW", EventAt: now.Add(72 * time.Hour), Tokenomics: old},
+		model.Event{ID: "okx:g:FAIL", Source: "okx", Token: "FAIL", EventAt: now.Add(73 * time.Hour), Tokenomics: old},
+		// Прошедшие события не обогащаются
+		model.Event{ID: "binance:h:PAST", Source: "binance", Token: "PAST", EventAt: now.Add(-time.Hour)},
+	)
+	supply := &fakeSupply{known: map[string]float64{"KITE": 150e6, "ZKJ": 40e6, "PAST": 1e6}}
+	a.tokenomics = supply
+
+	if n := a.EnrichTokenomics(context.Background()); n != 3 {
+		t.Errorf("updated %d events, want 3", n)
+	}
+	// Каждый тикер — один раз, ближайшие события — первыми
+	if want := []string{"ZKJ", "KITE", "NEW", "FAIL"}; !slices.Equal(supply.asked, want) {
+		t.Errorf("asked %v, want %v", supply.asked, want)
+	}
+	for id, mcap := range map[string]float64{"binance:a:KITE": 150e6, "bybit:b:KITE": 150e6, "okx:c:ZKJ": 40e6, "okx:f:NEW": 1, "okx:g:FAIL": 1} {
+		e, _, _ := a.store.Get(id)
+		if e.Tokenomics == nil || e.Tokenomics.MarketCap != mcap {
+			t.Errorf("%s tokenomics = %+v, want MCap %g", id, e.Tokenomics, mcap)
+		}
+	}
+	for _, id := range []string{"airdrops:d", "binance:e", "binance:h:PAST"} {
+		if e, _, _ := a.store.Get(id); e.Tokenomics != nil {
+			t.Errorf("%s enriched: %+v", id, e.Tokenomics)
+		}
+	}
+}
+
+func TestEnrichTokenomicsSingleFlight(t *testing.T) {
+	a := testAggregator(t, model.Event{ID: "okx:c:ZKJ", Token: "ZKJ", EventAt: time.Now().Add(time.Hour)})
+	supply := &fakeSupply{known: map[string]float64{"ZKJ": 40e6}}
+	a.tokenomics = supply
+
+	a.enriching.Lock()
+	if n := a.EnrichTokenomics(context.Background()); n != 0 || len(supply.asked) != 0 {
+		t.Errorf("concurrent call updated %d, asked %v", n, supply.asked)
+	}
+	a.enriching.Unlock()
+	if n := a.EnrichTokenomics(context.Background()); n != 1 {
+		t.Errorf("updated %d, want 1", n)
+	}
+}
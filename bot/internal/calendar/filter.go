@@ -1,21 +1,24 @@
 package calendar
 
 import (
+	"slices"
 	"sort"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
-// EventsForWeek возвращает события за последние 14 дней и следующие 7 дней
-func EventsForWeek(events []model.Event) []model.Event {
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	end := time.Now().UTC().Add(7 * 24 * time.Hour)
-	return filterAndSort(events, from, end)
+// EventsForWeek возвращает события в окне своего источника и типа
+// (по умолчанию последние 14 дней и следующие 7 дней)
+func EventsForWeek(events []model.Event, windows config.WindowsConfig) []model.Event {
+	return filterByWindow(events, windows)
 }
 
-// EventsTomorrow возвращает события завтра (для алерта за 24ч), которые ещё не отправлены
-func EventsTomorrow(events []model.Event) []model.Event {
+// EventsTomorrow возвращает события завтра (для алерта за 24ч), которые ещё не отправлены.
+// События с датой, выведенной из даты публикации, пропускаются — "завтра" для них ничего не значит;
+// разлоки — только уровней с alert_24h.
+func EventsTomorrow(events []model.Event, unlocks config.UnlocksConfig) []model.Event {
 	now := time.Now().UTC()
 	// Окно: 20–28 часов вперёд (чтобы не дублировать с более ранними проверками)
 	from := now.Add(20 * time.Hour)
@@ -23,14 +26,21 @@ func EventsTomorrow(events []model.Event) []model.Event {
 
 	var out []model.Event
 	for _, e := range events {
-		if !e.Sent24h && e.Date.After(from) && e.Date.Before(to) {
+		if e.Confidence() == model.DateInferred {
+			continue
+		}
+		if !unlockAllows(e, unlocks, func(t config.UnlockTier) bool { return t.Alert24h }) {
+			continue
+		}
+		if !e.Sent24h && e.EventAt.After(from) && e.EventAt.Before(to) {
 			out = append(out, e)
 		}
 	}
 	return sortByDate(out)
 }
 
-// EventsIn2Hours возвращает события через ~2 часа (листинги и TGE), которые ещё не отправлены
+// EventsIn2Hours возвращает события через ~2 часа (начало торгов и TGE), которые ещё не отправлены.
+// Нужно точное время: для событий "только дата" 00:00 UTC условно, и алерт пришёл бы невпопад.
 func EventsIn2Hours(events []model.Event) []model.Event {
 	now := time.Now().UTC()
 	from := now.Add(90 * time.Minute)
@@ -38,24 +48,28 @@ func EventsIn2Hours(events []model.Event) []model.Event {
 
 	var out []model.Event
 	for _, e := range events {
-		// Алерт за 2ч только для листингов и TGE/airdrop
-		if e.Type != model.EventListing && e.Type != model.EventAirdrop {
+		// Алерт за 2ч только для начала торгов (листинг, pre-market) и TGE/airdrop
+		switch e.Type {
+		case model.EventListing, model.EventPremarket, model.EventAirdrop, model.EventHodlerAirdrop:
+		default:
 			continue
 		}
-		if !e.Sent2h && e.Date.After(from) && e.Date.Before(to) {
+		if e.Confidence() != model.DateExact {
+			continue
+		}
+		if !e.Sent2h && e.EventAt.After(from) && e.EventAt.Before(to) {
 			out = append(out, e)
 		}
 	}
 	return sortByDate(out)
 }
 
-// EventsForDigest возвращает события (последние 14 дней + 7 дней вперёд), не попавшие в предыдущий дайджест
-func EventsForDigest(events []model.Event) []model.Event {
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	end := time.Now().UTC().Add(7 * 24 * time.Hour)
+// EventsForDigest возвращает события в окне своего источника и типа, не попавшие в предыдущий
+// дайджест; разлоки — только уровней с digest
+func EventsForDigest(events []model.Event, windows config.WindowsConfig, unlocks config.UnlocksConfig) []model.Event {
 	var out []model.Event
-	for _, e := range filterAndSort(events, from, end) {
-		if !e.SentDigest {
+	for _, e := range filterByWindow(events, windows) {
+		if !e.SentDigest && unlockAllows(e, unlocks, func(t config.UnlockTier) bool { return t.Digest }) {
 			out = append(out, e)
 		}
 	}
@@ -79,13 +93,33 @@ func EventsTomorrowAll(events []model.Event) []model.Event {
 	return filterAndSort(events, from, to)
 }
 
-// EventsUpcoming returns all future events of the given type, up to 30 days ahead.
-func EventsUpcoming(events []model.Event, evType model.EventType) []model.Event {
+// EventsUpcoming returns all future events of the given types, up to the
+// horizon of their own window (7 days by default, 45 for unlocks).
+func EventsUpcoming(events []model.Event, windows config.WindowsConfig, types ...model.EventType) []model.Event {
+	now := time.Now().UTC()
+	var out []model.Event
+	for _, e := range events {
+		to := now.Add(windows.For(e.Source, string(e.Type)).Horizon)
+		if slices.Contains(types, e.Type) && e.EventAt.After(now) && e.EventAt.Before(to) {
+			out = append(out, e)
+		}
+	}
+	return sortByDate(out)
+}
+
+// DigestPeriod возвращает период дайджеста: от текущего момента до самого
+// дальнего горизонта окон — в дайджест попадают и разлоки на 45 дней вперёд.
+func DigestPeriod(windows config.WindowsConfig) (from, to time.Time) {
+	now := time.Now().UTC()
+	return now, now.Add(windows.MaxHorizon())
+}
+
+// filterByWindow оставляет события, попадающие в окно для их источника и типа
+func filterByWindow(events []model.Event, windows config.WindowsConfig) []model.Event {
 	now := time.Now().UTC()
-	to := now.Add(30 * 24 * time.Hour)
 	var out []model.Event
 	for _, e := range events {
-		if e.Type == evType && e.Date.After(now) && e.Date.Before(to) {
+		if windows.For(e.Source, string(e.Type)).Contains(now, e.EventAt) {
 			out = append(out, e)
 		}
 	}
@@ -95,7 +129,7 @@ func EventsUpcoming(events []model.Event, evType model.EventType) []model.Event
 func filterAndSort(events []model.Event, from, to time.Time) []model.Event {
 	var out []model.Event
 	for _, e := range events {
-		if e.Date.After(from) && e.Date.Before(to) {
+		if e.EventAt.After(from) && e.EventAt.Before(to) {
 			out = append(out, e)
 		}
 	}
@@ -104,7 +138,7 @@ func filterAndSort(events []model.Event, from, to time.Time) []model.Event {
 
 func sortByDate(events []model.Event) []model.Event {
 	sort.Slice(events, func(i, j int) bool {
-		return events[i].Date.Before(events[j].Date)
+		return events[i].EventAt.Before(events[j].EventAt)
 	})
 	return events
 }
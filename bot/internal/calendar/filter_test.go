@@ -0,0 +1,122 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+)
+
+func TestEventsUpcomingUsesWindows(t *testing.T) {
+	h45 := 45
+	windows := config.WindowsConfig{
+		Types: map[string]config.WindowConfig{"unlock": {HorizonDays: &h45}},
+	}
+	now := time.Now().UTC()
+	day := 24 * time.Hour
+	events := []model.Event{
+		{ID: "unlock-40d", Type: model.EventUnlock, Source: "tokenunlocks", EventAt: now.Add(40 * day)},
+		{ID: "unlock-50d", Type: model.EventUnlock, Source: "tokenunlocks", EventAt: now.Add(50 * day)},
+		{ID: "unlock-past", Type: model.EventUnlock, Source: "tokenunlocks", EventAt: now.Add(-day)},
+		{ID: "listing-3d", Type: model.EventListing, Source: "binance", EventAt: now.Add(3 * day)},
+		{ID: "listing-10d", Type: model.EventListing, Source: "binance", EventAt: now.Add(10 * day)},
+	}
+
+	tests := []struct {
+		types []model.EventType
+		want  []string
+	}{
+		{[]model.EventType{model.EventUnlock}, []string{"unlock-40d"}},
+		{[]model.EventType{model.EventListing}, []string{"listing-3d"}},
+		{[]model.EventType{model.EventUnlock, model.EventListing}, []string{"listing-3d", "unlock-40d"}},
+	}
+	for _, tt := range tests {
+		got := EventsUpcoming(events, windows, tt.types...)
+		var ids []string
+		for _, e := range got {
+			ids = append(ids, e.ID)
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.types, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("%v: got %v, want %v", tt.types, ids, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, to := DigestPeriod(windows); to.Sub(now) < 45*day-time.Minute {
+		t.Errorf("DigestPeriod ends %s after now, want 45 days", to.Sub(now))
+	}
+}
+
+func TestUnlockTiersGateDigestAnd24h(t *testing.T) {
+	now := time.Now().UTC()
+	tomorrow := now.Add(24 * time.Hour)
+	unlock := func(id string, size *model.Unlock) model.Event {
+		return model.Event{ID: id, Type: model.EventUnlock, Source: "tokenunlocks", Token: id, EventAt: tomorrow, Unlock: size}
+	}
+	shared := &model.Unlock{Percent: 6, ValueUSD: 1e6}
+	events := []model.Event{
+		unlock("MAJOR", shared),
+		unlock("NOTABLE", &model.Unlock{Percent: 0.5, ValueUSD: 20e6}),
+		unlock("MINOR", &model.Unlock{Percent: 0.1, ValueUSD: 1e5}),
+		unlock("NOSIZE", nil),
+		{ID: "LISTING", Type: model.EventListing, Source: "binance", Token: "KITE", EventAt: tomorrow, DateConfidence: model.DateExact},
+	}
+
+	a := &Aggregator{}
+	ranked := a.rankUnlocks(slices.Clone(events))
+	wantTier := map[string]struct {
+		name string
+		n    int
+	}{"MAJOR": {"major", 0}, "NOTABLE": {"notable", 1}, "MINOR": {"minor", 2}, "NOSIZE": {"minor", 2}}
+	for _, e := range ranked {
+		w, ok := wantTier[e.ID]
+		if !ok {
+			if e.Unlock != nil {
+				t.Errorf("%s: non-unlock got a tier", e.ID)
+			}
+			continue
+		}
+		if e.Unlock == nil || e.Unlock.Severity != w.name || e.Unlock.Tier != w.n || e.Unlock.Icon == "" {
+			t.Errorf("%s: ranked %+v, want %s (%d)", e.ID, e.Unlock, w.name, w.n)
+		}
+	}
+	if shared.Severity != "" {
+		t.Error("rankUnlocks changed the shared unlock size")
+	}
+
+	ids := func(events []model.Event) []string {
+		var out []string
+		for _, e := range events {
+			out = append(out, e.ID)
+		}
+		slices.Sort(out)
+		return out
+	}
+	tests := []struct {
+		name string
+		got  []model.Event
+		want []string
+	}{
+		{"24h, default tiers", EventsTomorrow(ranked, config.UnlocksConfig{}), []string{"LISTING", "MAJOR"}},
+		{"digest, default tiers", EventsForDigest(ranked, config.WindowsConfig{}, config.UnlocksConfig{}),
+			[]string{"LISTING", "MAJOR", "NOTABLE"}},
+		// Один уровень без порогов — все разлоки в нём
+		{"24h, single tier", EventsTomorrow(ranked, config.UnlocksConfig{Tiers: []config.UnlockTier{{Name: "all", Alert24h: true}}}),
+			[]string{"LISTING", "MAJOR", "MINOR", "NOSIZE", "NOTABLE"}},
+		{"digest, single silent tier", EventsForDigest(ranked, config.WindowsConfig{}, config.UnlocksConfig{Tiers: []config.UnlockTier{{Name: "all"}}}),
+			[]string{"LISTING"}},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package calendar
+
+import (
+	"sort"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+)
+
+// mergePolicy — порядок источников и правила выбора полей сводного события
+// (merge в config.yaml).
+type mergePolicy struct {
+	rank   map[string]int      // source → место в priority, меньше — главнее
+	fields map[string][]string // поле → источники в порядке предпочтения
+}
+
+func newMergePolicy(cfg config.MergeConfig) mergePolicy {
+	p := mergePolicy{rank: make(map[string]int), fields: cfg.Fields}
+	for i, name := range cfg.SourcePriority() {
+		p.rank[name] = i + 1
+	}
+	return p
+}
+
+// priority returns a lower number for higher-priority sources; unknown sources go last.
+func (p mergePolicy) priority(source string) int {
+	if r, ok := p.rank[source]; ok {
+		return r
+	}
+	return 99
+}
+
+// merge merges duplicate events from different sources into one.
+// When the same TOKEN+DATE+TYPE appears from multiple sources, the highest-priority
+// source provides the primary fields, merge.fields may take single fields from
+// other sources, and every member becomes a Venue, ordered by start time.
+// Sent flags are preserved from whichever entry had them set.
+// The merge key is deliberately not the event ID: IDs identify an upstream
+// announcement, while the merge key says "the same happening on the same day".
+func (p mergePolicy) merge(events []model.Event) []model.Event {
+	type key struct {
+		token string
+		date  string
+		eType model.EventType
+	}
+
+	// Group events by cross-source key
+	groups := make(map[key][]model.Event)
+	var order []key // preserve insertion order for deterministic output
+	for _, e := range events {
+		k := key{
+			token: e.Token,
+			date:  e.EventAt.UTC().Format("20060102"),
+			eType: e.Type,
+		}
+		if _, exists := groups[k]; !exists {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], e)
+	}
+
+	out := make([]model.Event, 0, len(order))
+	for _, k := range order {
+		group := groups[k]
+		if len(group) == 1 {
+			out = append(out, group[0])
+			continue
+		}
+		// Highest priority first: the primary and the fallback for every field
+		sort.SliceStable(group, func(i, j int) bool {
+			return p.priority(group[i].Source) < p.priority(group[j].Source)
+		})
+		merged := group[0]
+
+		when := p.pick(config.MergeFieldDate, group, func(e model.Event) bool { return true })
+		merged.EventAt, merged.AnnouncedAt, merged.DateConfidence = when.EventAt, when.AnnouncedAt, when.Confidence()
+		merged.Title = p.pick(config.MergeFieldTitle, group, func(e model.Event) bool { return e.Title != "" }).Title
+		merged.Details = p.pick(config.MergeFieldDetails, group, func(e model.Event) bool { return e.Details != "" }).Details
+		merged.URL = p.pick(config.MergeFieldURL, group, func(e model.Event) bool { return e.URL != "" }).URL
+
+		// Every member is a venue; merge sent flags from all of them
+		merged.Venues = make([]model.Venue, 0, len(group))
+		for _, e := range group {
+			merged.Venues = append(merged.Venues, model.Venue{
+				ID:             e.ID,
+				Source:         e.Source,
+				Subtype:        e.Subtype,
+				EventAt:        e.EventAt,
+				AnnouncedAt:    e.AnnouncedAt,
+				DateConfidence: e.Confidence(),
+				URL:            e.URL,
+			})
+			if e.SentDigest {
+				merged.SentDigest = true
+			}
+			if e.Sent24h {
+				merged.Sent24h = true
+			}
+			if e.Sent2h {
+				merged.Sent2h = true
+			}
+		}
+		// Stable sort keeps priority order among venues starting at the same time
+		sort.SliceStable(merged.Venues, func(i, j int) bool {
+			return merged.Venues[i].EventAt.Before(merged.Venues[j].EventAt)
+		})
+		out = append(out, merged)
+	}
+	return out
+}
+
+// pick выбирает источник поля field по merge.fields среди событий group,
+// отсортированных по приоритету. has сообщает, есть ли у события значение
+// поля; без подходящего правила берётся первое событие со значением.
+func (p mergePolicy) pick(field string, group []model.Event, has func(model.Event) bool) model.Event {
+	for _, pref := range p.fields[field] {
+		switch pref {
+		case config.MergeExact:
+			for _, e := range group {
+				if e.Confidence() == model.DateExact {
+					return e
+				}
+			}
+		case config.MergeEarliest:
+			earliest := group[0]
+			for _, e := range group[1:] {
+				if e.EventAt.Before(earliest.EventAt) {
+					earliest = e
+				}
+			}
+			return earliest
+		default:
+			for _, e := range group {
+				if e.Source == pref && has(e) {
+					return e
+				}
+			}
+		}
+	}
+	for _, e := range group {
+		if has(e) {
+			return e
+		}
+	}
+	return group[0]
+}
@@ -0,0 +1,188 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+)
+
+// kiteListings — листинг одного токена на трёх биржах в один день
+// и повторный анонс Binance через два дня
+func kiteListings() []model.Event {
+	return []model.Event{
+		{ID: "binance-1", Source: "binance", Token: "KITE", Type: model.EventListing, Subtype: model.SubtypeSpotListing,
+			Title: "Binance Will List KITE", URL: "https://binance.example/1",
+			EventAt: utc("2026-03-05T10:00:00Z"), AnnouncedAt: utc("2026-03-02T09:00:00Z"), Sent24h: true},
+		{ID: "bybit-1", Source: "bybit", Token: "KITE", Type: model.EventListing, Subtype: model.SubtypeFuturesLaunch,
+			Title: "New Listing: KITEUSDT Perpetual", URL: "https://bybit.example/1",
+			EventAt: utc("2026-03-05T08:00:00Z"), AnnouncedAt: utc("2026-03-03T06:00:00Z"), SentDigest: true},
+		{ID: "okx-1", Source: "okx", Token: "KITE", Type: model.EventListing,
+			Title: "OKX to list KITE", Details: "Deposits open now",
+			EventAt: utc("2026-03-05T00:00:00Z"), AnnouncedAt: utc("2026-03-01T12:00:00Z"), DateConfidence: model.DateOnly},
+		{ID: "binance-2", Source: "binance", Token: "KITE", Type: model.EventListing, Subtype: model.SubtypeFuturesLaunch,
+			Title: "Binance Futures Will Launch KITEUSDT", EventAt: utc("2026-03-07T12:00:00Z")},
+	}
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func venueIDs(e model.Event) []string {
+	var ids []string
+	for _, v := range e.Venues {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func TestMergeSameTokenAcrossExchanges(t *testing.T) {
+	got := newMergePolicy(config.MergeConfig{}).merge(kiteListings())
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2 (one per day)", len(got))
+	}
+
+	merged := got[0]
+	// Основные поля — от Binance, первого в приоритете по умолчанию
+	if merged.ID != "binance-1" || merged.Title != "Binance Will List KITE" || merged.URL != "https://binance.example/1" {
+		t.Errorf("primary = %s %q %s", merged.ID, merged.Title, merged.URL)
+	}
+	if !merged.EventAt.Equal(utc("2026-03-05T10:00:00Z")) || merged.DateConfidence != model.DateExact {
+		t.Errorf("date = %v (%s), want Binance time", merged.EventAt, merged.DateConfidence)
+	}
+	// Details у Binance пусто — берётся следующий источник со значением
+	if merged.Details != "Deposits open now" {
+		t.Errorf("details = %q", merged.Details)
+	}
+	if !merged.SentDigest || !merged.Sent24h || merged.Sent2h {
+		t.Errorf("sent flags = digest %v, 24h %v, 2h %v", merged.SentDigest, merged.Sent24h, merged.Sent2h)
+	}
+
+	// Площадки упорядочены по времени старта, у каждой своё время
+	if ids := venueIDs(merged); !slices.Equal(ids, []string{"okx-1", "bybit-1", "binance-1"}) {
+		t.Errorf("venues = %v", ids)
+	}
+	okx := merged.Venues[0]
+	if okx.DateConfidence != model.DateOnly || okx.Source != "okx" || !okx.AnnouncedAt.Equal(utc("2026-03-01T12:00:00Z")) {
+		t.Errorf("okx venue = %+v", okx)
+	}
+	if bybit := merged.Venues[1]; bybit.Subtype != model.SubtypeFuturesLaunch || bybit.DateConfidence != model.DateExact {
+		t.Errorf("bybit venue = %+v", bybit)
+	}
+
+	// Другой день — другое событие, без списка площадок
+	if other := got[1]; other.ID != "binance-2" || len(other.Venues) != 0 {
+		t.Errorf("second event = %s with %d venues", other.ID, len(other.Venues))
+	}
+}
+
+func TestMergeFieldRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.MergeConfig
+		primary string
+		date    string
+		conf    model.DateConfidence
+		details string
+		url     string
+	}{
+		{
+			name:    "priority only",
+			cfg:     config.MergeConfig{Priority: []string{"bybit", "binance", "okx"}},
+			primary: "bybit-1", date: "2026-03-05T08:00:00Z", conf: model.DateExact,
+			details: "Deposits open now", url: "https://bybit.example/1",
+		},
+		{
+			name: "exact date from the first exact source",
+			cfg: config.MergeConfig{
+				Priority: []string{"okx", "bybit", "binance"},
+				Fields:   map[string][]string{config.MergeFieldDate: {config.MergeExact}},
+			},
+			primary: "okx-1", date: "2026-03-05T08:00:00Z", conf: model.DateExact,
+			// У OKX нет URL — берётся следующий по приоритету
+			details: "Deposits open now", url: "https://bybit.example/1",
+		},
+		{
+			name: "earliest date",
+			cfg: config.MergeConfig{
+				Fields: map[string][]string{config.MergeFieldDate: {config.MergeEarliest}},
+			},
+			primary: "binance-1", date: "2026-03-05T00:00:00Z", conf: model.DateOnly,
+			details: "Deposits open now", url: "https://binance.example/1",
+		},
+		{
+			name: "per-field sources",
+			cfg: config.MergeConfig{
+				Fields: map[string][]string{
+					config.MergeFieldDate:    {"bybit"},
+					config.MergeFieldDetails: {"binance", "okx"},
+					config.MergeFieldURL:     {"okx", "bybit"}, // у OKX URL пустой
+				},
+			},
+			primary: "binance-1", date: "2026-03-05T08:00:00Z", conf: model.DateExact,
+			details: "Deposits open now", url: "https://bybit.example/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newMergePolicy(tt.cfg).merge(kiteListings())[0]
+			if got.ID != tt.primary {
+				t.Errorf("primary = %s, want %s", got.ID, tt.primary)
+			}
+			if !got.EventAt.Equal(utc(tt.date)) || got.DateConfidence != tt.conf {
+				t.Errorf("date = %v (%s), want %s (%s)", got.EventAt, got.DateConfidence, tt.date, tt.conf)
+			}
+			if got.Details != tt.details {
+				t.Errorf("details = %q, want %q", got.Details, tt.details)
+			}
+			if got.URL != tt.url {
+				t.Errorf("url = %q, want %q", got.URL, tt.url)
+			}
+			// Порядок площадок не зависит от политики
+			if ids := venueIDs(got); !slices.Equal(ids, []string{"okx-1", "bybit-1", "binance-1"}) {
+				t.Errorf("venues = %v", ids)
+			}
+		})
+	}
+}
+
+func TestMergeUnknownSourceGoesLast(t *testing.T) {
+	events := []model.Event{
+		{ID: "mexc-1", Source: "mexc", Token: "KITE", Type: model.EventListing, Title: "MEXC", EventAt: utc("2026-03-05T06:00:00Z")},
+		{ID: "okx-1", Source: "okx", Token: "KITE", Type: model.EventListing, Title: "OKX", EventAt: utc("2026-03-05T09:00:00Z")},
+	}
+	got := newMergePolicy(config.MergeConfig{}).merge(events)
+	if len(got) != 1 || got[0].ID != "okx-1" {
+		t.Fatalf("got %+v, want okx-1 as primary", got)
+	}
+	if ids := venueIDs(got[0]); !slices.Equal(ids, []string{"mexc-1", "okx-1"}) {
+		t.Errorf("venues = %v", ids)
+	}
+}
+
+func TestMergeKeepsDifferentDaysApart(t *testing.T) {
+	// Ключ слияния — календарный день в UTC: старты по обе стороны полуночи
+	// остаются разными событиями, как и разные типы в один день
+	events := []model.Event{
+		{ID: "bybit-1", Source: "bybit", Token: "KITE", Type: model.EventListing, EventAt: utc("2026-03-05T23:30:00Z")},
+		{ID: "binance-1", Source: "binance", Token: "KITE", Type: model.EventListing, EventAt: utc("2026-03-06T01:00:00Z")},
+		{ID: "binance-2", Source: "binance", Token: "KITE", Type: model.EventAirdrop, EventAt: utc("2026-03-05T23:30:00Z")},
+		{ID: "okx-1", Source: "okx", Token: "OTHER", Type: model.EventListing, EventAt: utc("2026-03-05T23:30:00Z")},
+	}
+	got := newMergePolicy(config.MergeConfig{}).merge(events)
+	if len(got) != len(events) {
+		t.Fatalf("got %d events, want %d", len(got), len(events))
+	}
+	for i, e := range got {
+		if e.ID != events[i].ID || len(e.Venues) != 0 {
+			t.Errorf("event %d = %s with %d venues, want %s alone", i, e.ID, len(e.Venues), events[i].ID)
+		}
+	}
+}
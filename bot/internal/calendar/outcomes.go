@@ -0,0 +1,251 @@
+package calendar
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"crypto-bot/internal/market"
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
+)
+
+// outcomeBatch — сколько событий архива оценивать за один вызов TrackOutcomes,
+// чтобы не упираться в лимиты публичного API
+const outcomeBatch = 50
+
+// outcomeTracker — откуда брать свечи для реакции цены
+type outcomeTracker struct {
+	client market.Client
+	quote  string // котируемая валюта пары
+}
+
+// outcomeWindow — окно реакции: последний из горизонтов
+func outcomeWindow() time.Duration {
+	return model.OutcomeHorizons[len(model.OutcomeHorizons)-1].After
+}
+
+// TrackOutcomes дописывает реакцию цены событиям архива: впервые — сразу
+// после переноса в архив, повторно — когда пройдёт окно в 7 дней; после
+// этого реакция окончательная. События без известной даты пропускаются.
+// Возвращает, сколько событий обновлено.
+func (a *Aggregator) TrackOutcomes(ctx context.Context) int {
+	now := time.Now().UTC()
+	past, err := a.archive.Query(store.Query{To: now})
+	if err != nil {
+		log.Printf("[outcomes] query archive: %v", err)
+		return 0
+	}
+
+	// Одинаковый токен в одно время у разных бирж — одни и те же свечи
+	type key struct {
+		token string
+		at    time.Time
+	}
+	seen := make(map[key]*model.Outcome)
+	var updated []model.Event
+	for _, e := range past {
+		if len(updated) >= outcomeBatch || ctx.Err() != nil {
+			break
+		}
+		if e.Confidence() == model.DateInferred || !needsOutcome(e, now) {
+			continue
+		}
+		k := key{e.Token, e.EventAt}
+		o, ok := seen[k]
+		if !ok {
+			o, err = a.outcomes.measure(ctx, e, now)
+			if err != nil {
+				log.Printf("[outcomes] %s: %v", e.ID, err)
+				continue
+			}
+			seen[k] = o
+		}
+		e.Outcome = o
+		updated = append(updated, e)
+	}
+	if len(updated) == 0 {
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.archive.Upsert(updated...); err != nil {
+		log.Printf("[outcomes] save: %v", err)
+		return 0
+	}
+	log.Printf("[outcomes] updated %d events", len(updated))
+	return len(updated)
+}
+
+// needsOutcome сообщает, пора ли (пере)считать реакцию цены на событие
+func needsOutcome(e model.Event, now time.Time) bool {
+	if e.Outcome == nil {
+		return true
+	}
+	if e.Outcome.Complete {
+		return false
+	}
+	start := e.Outcome.StartAt
+	if start.IsZero() {
+		start = e.EventAt
+	}
+	return !now.Before(start.Add(outcomeWindow()))
+}
+
+// measure считает реакцию цены по часовым свечам от первой свечи после
+// события до конца окна (или до now, если окно ещё не прошло).
+func (t outcomeTracker) measure(ctx context.Context, e model.Event, now time.Time) (*model.Outcome, error) {
+	pair := market.NewPair(e.Token, t.quote)
+	o := &model.Outcome{
+		Provider:  t.client.Name(),
+		Symbol:    pair.String(),
+		CheckedAt: now,
+	}
+	from := e.EventAt.Truncate(time.Hour)
+	klines, err := t.client.Klines(ctx, market.KlineQuery{
+		Category: market.Spot,
+		Pair:     pair,
+		Interval: market.Hour1,
+		From:     from,
+		To:       minTime(from.Add(outcomeWindow()), now),
+	})
+	if errors.Is(err, market.ErrUnknownSymbol) {
+		o.NoMarket, o.Complete = true, true
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		// Торги так и не начались за всё окно — считаем, что рынка нет
+		if !now.Before(from.Add(outcomeWindow())) {
+			o.NoMarket, o.Complete = true, true
+			return o, nil
+		}
+		return o, nil
+	}
+
+	// Для листинга с одной датой торги начинаются позже 00:00 — горизонты
+	// считаются от первой свечи, и хвост окна после from+окно догружается
+	o.StartAt, o.PriceAt = klines[0].OpenTime, klines[0].Open
+	end := o.StartAt.Add(outcomeWindow())
+	if tailFrom, tailTo := from.Add(outcomeWindow()), minTime(end, now); tailTo.After(tailFrom) {
+		tail, err := t.client.Klines(ctx, market.KlineQuery{
+			Category: market.Spot,
+			Pair:     pair,
+			Interval: market.Hour1,
+			From:     tailFrom,
+			To:       tailTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, tail...)
+	}
+	o.Prices = make(map[string]float64)
+	for _, h := range model.OutcomeHorizons {
+		at := o.StartAt.Add(h.After)
+		if now.Before(at) {
+			break
+		}
+		// Цена на горизонте — закрытие последней свечи перед ним
+		i := sort.Search(len(klines), func(i int) bool { return !klines[i].OpenTime.Before(at) })
+		if i > 0 {
+			o.Prices[h.Name] = klines[i-1].Close
+		}
+	}
+	high, low := o.PriceAt, o.PriceAt
+	for _, k := range klines {
+		if !k.OpenTime.Before(end) {
+			break
+		}
+		high, low = max(high, k.High), min(low, k.Low)
+	}
+	if o.PriceAt > 0 {
+		o.MaxDrawup = (high/o.PriceAt - 1) * 100
+		o.MaxDrawdown = (low/o.PriceAt - 1) * 100
+	}
+	o.Complete = !now.Before(end)
+	return o, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// OutcomeStats — средняя реакция цены на события одного типа из одного источника
+type OutcomeStats struct {
+	Type   model.EventType
+	Source string
+	Count  int // событий с известной ценой
+	// AvgReturn — горизонт → средняя доходность в %, по событиям, дошедшим до горизонта
+	AvgReturn   map[string]float64
+	AvgDrawup   float64
+	AvgDrawdown float64
+}
+
+// OutcomeReport сводит реакцию цены на события архива в [from, to) по типу
+// и источнику. Порядок — по типу, затем по источнику.
+func (a *Aggregator) OutcomeReport(from, to time.Time) []OutcomeStats {
+	events, err := a.archive.Query(store.Query{From: from, To: to})
+	if err != nil {
+		log.Printf("[outcomes] query archive: %v", err)
+		return nil
+	}
+	type key struct {
+		eType  model.EventType
+		source string
+	}
+	type acc struct {
+		stats  OutcomeStats
+		counts map[string]int
+	}
+	groups := make(map[key]*acc)
+	for _, e := range events {
+		o := e.Outcome
+		if o == nil || o.NoMarket || o.PriceAt == 0 {
+			continue
+		}
+		k := key{e.Type, e.Source}
+		g, ok := groups[k]
+		if !ok {
+			g = &acc{
+				stats:  OutcomeStats{Type: e.Type, Source: e.Source, AvgReturn: make(map[string]float64)},
+				counts: make(map[string]int),
+			}
+			groups[k] = g
+		}
+		g.stats.Count++
+		g.stats.AvgDrawup += o.MaxDrawup
+		g.stats.AvgDrawdown += o.MaxDrawdown
+		for _, h := range model.OutcomeHorizons {
+			if r, ok := o.Return(h.Name); ok {
+				g.stats.AvgReturn[h.Name] += r
+				g.counts[h.Name]++
+			}
+		}
+	}
+
+	out := make([]OutcomeStats, 0, len(groups))
+	for _, g := range groups {
+		s := g.stats
+		s.AvgDrawup /= float64(s.Count)
+		s.AvgDrawdown /= float64(s.Count)
+		for h, n := range g.counts {
+			s.AvgReturn[h] /= float64(n)
+		}
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(x, y OutcomeStats) int {
+		return cmp.Or(cmp.Compare(x.Type, y.Type), cmp.Compare(x.Source, y.Source))
+	})
+	return out
+}
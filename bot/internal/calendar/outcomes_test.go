@@ -0,0 +1,294 @@
+package calendar
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/market"
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
+)
+
+// stubMarket отдаёт свечи из заранее заданного ряда по [From, To) запроса
+type stubMarket struct {
+	klines []market.Kline
+	err    error
+}
+
+func (s *stubMarket) Name() string { return "stub" }
+
+func (s *stubMarket) Klines(_ context.Context, q market.KlineQuery) ([]market.Kline, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var out []market.Kline
+	for _, k := range s.klines {
+		if !k.OpenTime.Before(q.From) && k.OpenTime.Before(q.To) {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubMarket) Ticker(context.Context, market.Category, market.Pair) (market.Ticker, error) {
+	return market.Ticker{}, nil
+}
+
+func (s *stubMarket) Funding(context.Context, market.Pair) (market.Funding, error) {
+	return market.Funding{}, nil
+}
+
+func (s *stubMarket) OpenInterest(context.Context, market.Pair) (market.OpenInterest, error) {
+	return market.OpenInterest{}, nil
+}
+
+func (s *stubMarket) Instruments(context.Context, market.Category) ([]market.Instrument, error) {
+	return nil, nil
+}
+
+// candlePrice — цена i-й свечи тестового ряда: +1% от старта за каждый час
+func candlePrice(i int) float64 { return 1 + 0.01*float64(i) }
+
+// hourlyCandles строит n часовых свечей от start; spikes — high/low отдельных свечей
+func hourlyCandles(start time.Time, n int, highs, lows map[int]float64) []market.Kline {
+	out := make([]market.Kline, n)
+	for i := range out {
+		p := candlePrice(i)
+		out[i] = market.Kline{OpenTime: start.Add(time.Duration(i) * time.Hour), Open: p, High: p, Low: p, Close: p}
+		if h, ok := highs[i]; ok {
+			out[i].High = h
+		}
+		if l, ok := lows[i]; ok {
+			out[i].Low = l
+		}
+	}
+	return out
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b)) }
+
+func TestOutcomeMeasure(t *testing.T) {
+	day0 := utc("2026-03-05T00:00:00Z")
+	open := utc("2026-03-05T10:00:00Z") // торги по листингу с одной датой
+	week := outcomeWindow()
+	// Всплеск в последние часы окна от первой свечи — после day0+7д
+	full := hourlyCandles(open, 8*24, map[int]float64{165: 10}, map[int]float64{2: 0.5})
+
+	tests := []struct {
+		name    string
+		event   model.Event
+		client  *stubMarket
+		now     time.Time
+		start   time.Time
+		prices  map[string]float64
+		drawup  float64
+		down    float64
+		noMkt   bool
+		done    bool
+		wantErr bool
+	}{
+		{
+			name:   "date-only start",
+			event:  model.Event{Token: "KITE", EventAt: day0, DateConfidence: model.DateOnly},
+			client: &stubMarket{klines: full},
+			now:    day0.Add(10 * 24 * time.Hour),
+			start:  open,
+			prices: map[string]float64{"1h": candlePrice(0), "4h": candlePrice(3), "24h": candlePrice(23), "7d": candlePrice(167)},
+			drawup: 900, down: -50, done: true,
+		},
+		{
+			name:   "window not over",
+			event:  model.Event{Token: "KITE", EventAt: open, DateConfidence: model.DateExact},
+			client: &stubMarket{klines: full},
+			now:    open.Add(30 * time.Hour),
+			start:  open,
+			prices: map[string]float64{"1h": candlePrice(0), "4h": candlePrice(3), "24h": candlePrice(23)},
+			drawup: 29, down: -50,
+		},
+		{
+			name:   "unknown symbol",
+			event:  model.Event{Token: "KITE", EventAt: open},
+			client: &stubMarket{err: market.ErrUnknownSymbol},
+			now:    open.Add(time.Hour),
+			noMkt:  true, done: true,
+		},
+		{
+			name:    "provider error",
+			event:   model.Event{Token: "KITE", EventAt: open},
+			client:  &stubMarket{err: market.ErrRateLimited},
+			now:     open.Add(time.Hour),
+			wantErr: true,
+		},
+		{
+			name:   "no candles yet",
+			event:  model.Event{Token: "KITE", EventAt: day0, DateConfidence: model.DateOnly},
+			client: &stubMarket{},
+			now:    day0.Add(3 * 24 * time.Hour),
+		},
+		{
+			name:   "no candles for the whole window",
+			event:  model.Event{Token: "KITE", EventAt: day0, DateConfidence: model.DateOnly},
+			client: &stubMarket{klines: hourlyCandles(day0.Add(week), 24, nil, nil)},
+			now:    day0.Add(week + 2*24*time.Hour),
+			noMkt:  true, done: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := outcomeTracker{client: tt.client, quote: "USDT"}.measure(context.Background(), tt.event, tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("measure = %+v, want error", o)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.Symbol != "KITE/USDT" || o.Provider != "stub" || !o.CheckedAt.Equal(tt.now) {
+				t.Errorf("symbol %s, provider %s, checked %v", o.Symbol, o.Provider, o.CheckedAt)
+			}
+			if o.NoMarket != tt.noMkt || o.Complete != tt.done || !o.StartAt.Equal(tt.start) {
+				t.Errorf("no market %v, complete %v, start %v; want %v, %v, %v",
+					o.NoMarket, o.Complete, o.StartAt, tt.noMkt, tt.done, tt.start)
+			}
+			if len(o.Prices) != len(tt.prices) {
+				t.Errorf("prices = %v, want %v", o.Prices, tt.prices)
+			}
+			for h, want := range tt.prices {
+				if got, ok := o.Prices[h]; !ok || !approx(got, want) {
+					t.Errorf("price %s = %v, want %v", h, got, want)
+				}
+			}
+			if !approx(o.MaxDrawup, tt.drawup) || !approx(o.MaxDrawdown, tt.down) {
+				t.Errorf("drawup %v, drawdown %v; want %v, %v", o.MaxDrawup, o.MaxDrawdown, tt.drawup, tt.down)
+			}
+		})
+	}
+}
+
+func TestOutcomeRemeasure(t *testing.T) {
+	open := utc("2026-03-05T10:00:00Z")
+	e := model.Event{Token: "KITE", EventAt: utc("2026-03-05T00:00:00Z"), DateConfidence: model.DateOnly}
+	tr := outcomeTracker{client: &stubMarket{klines: hourlyCandles(open, 8*24, nil, nil)}, quote: "USDT"}
+
+	partial := open.Add(30 * time.Hour)
+	o, err := tr.measure(context.Background(), e, partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Complete {
+		t.Fatal("complete before the window is over")
+	}
+	e.Outcome = o
+
+	// Окно отсчитывается от первой свечи, а не от 00:00 даты события
+	if needsOutcome(e, e.EventAt.Add(outcomeWindow()+time.Hour)) {
+		t.Error("re-measure before StartAt + window")
+	}
+	end := open.Add(outcomeWindow())
+	if !needsOutcome(e, end) {
+		t.Fatal("no re-measure after StartAt + window")
+	}
+	if e.Outcome, err = tr.measure(context.Background(), e, end); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := e.Outcome.Prices["7d"]; !e.Outcome.Complete || !ok || !approx(p, candlePrice(167)) {
+		t.Errorf("complete %v, 7d price %v", e.Outcome.Complete, p)
+	}
+	if needsOutcome(e, end.Add(24*time.Hour)) {
+		t.Error("complete outcome measured again")
+	}
+}
+
+func TestNeedsOutcome(t *testing.T) {
+	at := utc("2026-03-05T00:00:00Z")
+	start := utc("2026-03-05T10:00:00Z")
+	week := outcomeWindow()
+	tests := []struct {
+		name    string
+		outcome *model.Outcome
+		now     time.Time
+		want    bool
+	}{
+		{"never measured", nil, at.Add(time.Hour), true},
+		{"complete", &model.Outcome{StartAt: start, Complete: true}, start.Add(2 * week), false},
+		{"partial, window running", &model.Outcome{StartAt: start}, start.Add(week - time.Hour), false},
+		{"partial, window over", &model.Outcome{StartAt: start}, start.Add(week), true},
+		{"no candles, window running", &model.Outcome{}, at.Add(week - time.Hour), false},
+		{"no candles, window over", &model.Outcome{}, at.Add(week), true},
+	}
+	for _, tt := range tests {
+		e := model.Event{EventAt: at, Outcome: tt.outcome}
+		if got := needsOutcome(e, tt.now); got != tt.want {
+			t.Errorf("%s: needsOutcome = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOutcomeReport(t *testing.T) {
+	archive, err := store.OpenJSON(filepath.Join(t.TempDir(), "archive.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := utc("2026-03-01T00:00:00Z"), utc("2026-03-08T00:00:00Z")
+	at := utc("2026-03-03T10:00:00Z")
+	outcome := func(up, down float64, prices map[string]float64) *model.Outcome {
+		return &model.Outcome{StartAt: at, PriceAt: 1, Prices: prices, MaxDrawup: up, MaxDrawdown: down}
+	}
+	err = archive.Upsert(
+		model.Event{ID: "binance:a:KITE", Type: model.EventListing, Source: "binance", EventAt: at,
+			Outcome: outcome(40, -10, map[string]float64{"1h": 1.2, "24h": 1.1})},
+		model.Event{ID: "binance:b:ZKJ", Type: model.EventListing, Source: "binance", EventAt: at,
+			Outcome: outcome(20, -30, map[string]float64{"1h": 0.9})},
+		// Без рынка и без оценки — в средние не входят
+		model.Event{ID: "binance:c:BIRB", Type: model.EventListing, Source: "binance", EventAt: at,
+			Outcome: &model.Outcome{NoMarket: true, Complete: true}},
+		model.Event{ID: "binance:d:OPN", Type: model.EventListing, Source: "binance", EventAt: at},
+		model.Event{ID: "okx:e:KITE", Type: model.EventListing, Source: "okx", EventAt: at,
+			Outcome: outcome(5, -5, map[string]float64{"7d": 0.5})},
+		model.Event{ID: "binance:f:ARB", Type: model.EventLaunchpool, Source: "binance", EventAt: at,
+			Outcome: outcome(1, -1, nil)},
+		// Вне периода отчёта
+		model.Event{ID: "binance:g:SOL", Type: model.EventListing, Source: "binance", EventAt: to.Add(time.Hour),
+			Outcome: outcome(100, 0, nil)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := (&Aggregator{archive: archive}).OutcomeReport(from, to)
+	want := []struct {
+		eType   model.EventType
+		source  string
+		count   int
+		up      float64
+		down    float64
+		returns map[string]float64
+	}{
+		{model.EventLaunchpool, "binance", 1, 1, -1, map[string]float64{}},
+		{model.EventListing, "binance", 2, 30, -20, map[string]float64{"1h": 5, "24h": 10}},
+		{model.EventListing, "okx", 1, 5, -5, map[string]float64{"7d": -50}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("report = %+v", got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Type != w.eType || g.Source != w.source || g.Count != w.count ||
+			!approx(g.AvgDrawup, w.up) || !approx(g.AvgDrawdown, w.down) {
+			t.Errorf("row %d = %+v, want %+v", i, g, w)
+		}
+		if len(g.AvgReturn) != len(w.returns) {
+			t.Errorf("row %d returns = %v, want %v", i, g.AvgReturn, w.returns)
+		}
+		for h, r := range w.returns {
+			if !approx(g.AvgReturn[h], r) {
+				t.Errorf("row %d return %s = %v, want %v", i, h, g.AvgReturn[h], r)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+
+	"crypto-bot/internal/scanner"
+)
+
+// SourceStatus — здоровье источника по итогам последних обновлений.
+type SourceStatus struct {
+	Name        string
+	LastRun     time.Time // последний опрос (успешный или нет)
+	LastSuccess time.Time // последний успешный опрос
+	LastError   string    // ошибка последнего опроса, пусто при успехе
+	Failures    int       // неудачных опросов подряд
+	Endpoint    string    // эндпоинт, отдавший данные в последний успешный опрос
+
+	// Счётчики последнего успешного опроса
+	Fetched int // сырых записей от источника
+	Parsed  int // событий после разбора и фильтра по окну
+	Kept    int // событий после межисточниковой дедупликации
+
+	alerted bool // предупреждение о падении уже отправлено
+}
+
+// record обновляет статус по итогам одного опроса
+func (s *SourceStatus) record(now time.Time, res scanner.Result, err error, kept int) {
+	s.LastRun = now
+	if err != nil {
+		s.LastError = err.Error()
+		s.Failures++
+		return
+	}
+	s.LastSuccess = now
+	s.LastError = ""
+	s.Failures = 0
+	s.alerted = false
+	s.Endpoint = res.Endpoint
+	s.Fetched = res.Fetched
+	s.Parsed = len(res.Events)
+	s.Kept = kept
+}
+
+// Status возвращает снимок здоровья всех источников, отсортированный по имени.
+func (a *Aggregator) Status() []SourceStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]SourceStatus, 0, len(a.status))
+	for _, s := range a.status {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// FailingSources возвращает источники, упавшие threshold раз подряд, о которых
+// ещё не предупреждали, и помечает их как предупреждённые. После успешного
+// опроса источник снова может попасть в список.
+func (a *Aggregator) FailingSources(threshold int) []SourceStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var out []SourceStatus
+	for _, s := range a.status {
+		if s.alerted || s.Failures < threshold {
+			continue
+		}
+		s.alerted = true
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
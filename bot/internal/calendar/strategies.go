@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"time"
+
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
+	"crypto-bot/internal/strategy"
+)
+
+// Strategies возвращает стратегии, которые подходят событию и показываются
+// в алерте alert, в порядке приоритета, с разбором фильтров.
+func (a *Aggregator) Strategies(e model.Event, alert strategy.Alert) []strategy.Match {
+	var out []strategy.Match
+	for _, m := range a.strategies.Engine().Qualify(e, a.facts(e)) {
+		if m.Strategy.AlertsIn(alert) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// StrategyList возвращает действующие стратегии в порядке приоритета
+func (a *Aggregator) StrategyList() []strategy.Strategy {
+	return a.strategies.Engine().Strategies()
+}
+
+// ReloadStrategies перечитывает файл стратегий, если он изменился.
+// true — стратегии заменены; при ошибке остаются прежние.
+func (a *Aggregator) ReloadStrategies() (bool, error) {
+	return a.strategies.Reload()
+}
+
+// StrategiesFile возвращает путь к файлу стратегий; пусто — встроенные
+func (a *Aggregator) StrategiesFile() string {
+	return a.strategies.Path()
+}
+
+// facts собирает метрики события для стратегий: токеномику самого события
+// и то, что календарь знает о токене из других событий.
+func (a *Aggregator) facts(e model.Event) strategy.Facts {
+	f := strategy.FactsFrom(e)
+	now := time.Now().UTC()
+
+	// Ближайший разлок; нет разлоков в календаре — нет данных, а не "никогда".
+	// Разлок с одной датой стоит на 00:00 — сегодняшний считаем с начала дня.
+	// Дальше окна разлоков календарь не видит, поэтому порог фильтра
+	// days_to_unlock ограничен этим окном (strategy.Limits в NewAggregator)
+	today := now.Truncate(24 * time.Hour)
+	unlocks, err := a.store.Query(store.Query{From: today, Types: []model.EventType{model.EventUnlock}, Token: e.Token})
+	if err == nil && len(unlocks) > 0 {
+		f[strategy.MetricDaysToUnlock] = max(0, unlocks[0].EventAt.Sub(now).Hours()/24)
+
+		// Сколько предложения выйдет за горизонт графика разлоков
+		until := now.Add(a.unlocks.ScheduleHorizon(a.windows))
+		ahead := 0.0
+		for _, u := range unlocks {
+			if u.Unlock != nil && u.EventAt.Before(until) {
+				ahead += u.Unlock.Percent
+			}
+		}
+		if ahead > 0 {
+			f[strategy.MetricUnlockAhead] = ahead / 100
+		}
+	}
+	premarket, err := a.store.Query(store.Query{Types: []model.EventType{model.EventPremarket}, Token: e.Token})
+	if err == nil && len(premarket) > 0 {
+		f[strategy.MetricPremarket] = 1
+	}
+	return f
+}
@@ -0,0 +1,39 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/strategy"
+)
+
+func TestFactsDaysToUnlock(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tests := []struct {
+		name    string
+		unlocks []time.Time
+		want    float64 // -1 — метрики нет
+	}{
+		{"no unlocks", nil, -1},
+		{"yesterday only", []time.Time{today.Add(-24 * time.Hour)}, -1},
+		// Разлок с одной датой на сегодня стоит на 00:00 — он уже наступает
+		{"today, date only", []time.Time{today, today.Add(10 * 24 * time.Hour)}, 0},
+		{"in ten days", []time.Time{today.Add(10 * 24 * time.Hour)}, 10},
+	}
+	for _, tt := range tests {
+		var stored []model.Event
+		for _, at := range tt.unlocks {
+			stored = append(stored, model.Event{ID: "tokenunlocks:ARB:" + at.Format("20060102"), Type: model.EventUnlock,
+				Source: "tokenunlocks", Token: "ARB", EventAt: at})
+		}
+		a := testAggregator(t, stored...)
+		got, ok := a.facts(model.Event{Type: model.EventListing, Token: "ARB"})[strategy.MetricDaysToUnlock]
+		switch {
+		case tt.want < 0 && ok:
+			t.Errorf("%s: days_to_unlock = %v, want none", tt.name, got)
+		case tt.want >= 0 && (!ok || got < tt.want-1 || got > tt.want):
+			t.Errorf("%s: days_to_unlock = %v (%v), want about %v", tt.name, got, ok, tt.want)
+		}
+	}
+}
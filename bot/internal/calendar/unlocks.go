@@ -0,0 +1,83 @@
+package calendar
+
+import (
+	"log"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
+)
+
+// Unlocks возвращает уровни разлоков, с которыми построен агрегатор.
+func (a *Aggregator) Unlocks() config.UnlocksConfig {
+	return a.unlocks
+}
+
+// rankUnlocks назначает разлокам уровень по unlocks.tiers. Размер
+// копируется: события из хранилища могут делить его с кэшем в памяти.
+func (a *Aggregator) rankUnlocks(events []model.Event) []model.Event {
+	for i, e := range events {
+		if e.Type != model.EventUnlock {
+			continue
+		}
+		var size model.Unlock
+		if e.Unlock != nil {
+			size = *e.Unlock
+		}
+		tier, n := a.unlocks.TierFor(e.Unlock)
+		size.Severity, size.Icon, size.Tier = tier.Name, tier.Icon, n
+		events[i].Unlock = &size
+	}
+	return events
+}
+
+// unlockAllows сообщает, пропускает ли уровень разлока уведомление: allow
+// выбирает флаг уровня (дайджест, алерт 24ч). Остальные события — всегда.
+func unlockAllows(e model.Event, unlocks config.UnlocksConfig, allow func(config.UnlockTier) bool) bool {
+	if e.Type != model.EventUnlock {
+		return true
+	}
+	tier, _ := unlocks.TierFor(e.Unlock)
+	return allow(tier)
+}
+
+// UnlockStep — одна дата графика разлоков токена
+type UnlockStep struct {
+	Event model.Event
+	// Cumulative — % общего предложения, который разлочится с сегодняшнего
+	// дня по эту дату включительно (разлоки неизвестного размера не входят)
+	Cumulative float64
+}
+
+// UnlockSchedule возвращает график предстоящих разлоков токена на горизонт
+// unlocks.schedule_days (по датам, с накопленной долей предложения) и сам
+// горизонт.
+func (a *Aggregator) UnlockSchedule(token string) ([]UnlockStep, time.Duration) {
+	horizon := a.unlocks.ScheduleHorizon(a.windows)
+	now := time.Now().UTC()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// С начала суток: разлоки приходят датой без времени (00:00 UTC)
+	events, err := a.store.Query(store.Query{
+		From:  now.Truncate(24 * time.Hour),
+		To:    now.Add(horizon),
+		Types: []model.EventType{model.EventUnlock},
+		Token: token,
+	})
+	if err != nil {
+		log.Printf("[aggregator] query unlock schedule: %v", err)
+		return nil, horizon
+	}
+
+	steps := make([]UnlockStep, 0, len(events))
+	cumulative := 0.0
+	for _, e := range sortByDate(a.rankUnlocks(a.merge.merge(events))) {
+		if e.Unlock != nil {
+			cumulative += e.Unlock.Percent
+		}
+		steps = append(steps, UnlockStep{Event: e, Cumulative: cumulative})
+	}
+	return steps, horizon
+}
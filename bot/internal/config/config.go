@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +14,16 @@ type Config struct {
 	Schedule ScheduleConfig `yaml:"schedule"`
 	Scanner  ScannerConfig  `yaml:"scanner"`
 	Sources  SourcesConfig  `yaml:"sources"`
+	Windows  WindowsConfig  `yaml:"windows"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Merge    MergeConfig    `yaml:"merge"`
+	Market   MarketConfig   `yaml:"market"`
+	// Tokenomics — откуда брать предложение, MCap и FDV токенов событий
+	Tokenomics TokenomicsConfig `yaml:"tokenomics"`
+	// Unlocks — уровни серьёзности разлоков
+	Unlocks UnlocksConfig `yaml:"unlocks"`
+	// Strategies — файл с торговыми стратегиями для алертов
+	Strategies StrategiesConfig `yaml:"strategies"`
 }
 
 type TelegramConfig struct {
@@ -26,14 +38,110 @@ type ScheduleConfig struct {
 
 type ScannerConfig struct {
 	RefreshIntervalMinutes int `yaml:"refresh_interval_minutes"`
+	// FailureAlertAfter — после скольких неудачных опросов подряд
+	// прислать предупреждение о недоступном источнике
+	FailureAlertAfter int `yaml:"failure_alert_after"`
+	// RulesFile — YAML с правилами классификации анонсов бирж;
+	// пусто — встроенные правила (internal/scanner/rules.yaml)
+	RulesFile string `yaml:"rules_file"`
+	// ScanTimeoutSeconds — сколько ждать один источник за обновление: листание
+	// страниц анонсов плюс догрузка до 10 текстов анонсов; 0 — 90 секунд
+	ScanTimeoutSeconds int `yaml:"scan_timeout_seconds"`
 }
 
-type SourcesConfig struct {
-	Bybit        bool `yaml:"bybit"`
-	Binance      bool `yaml:"binance"`
-	OKX          bool `yaml:"okx"`
-	TokenUnlocks bool `yaml:"tokenunlocks"`
-	Airdrops     bool `yaml:"airdrops"`
+// ScanTimeout возвращает предел опроса одного источника с учётом значения по умолчанию.
+func (s ScannerConfig) ScanTimeout() time.Duration {
+	if s.ScanTimeoutSeconds <= 0 {
+		return 90 * time.Second
+	}
+	return time.Duration(s.ScanTimeoutSeconds) * time.Second
+}
+
+// StorageConfig — где хранить события.
+type StorageConfig struct {
+	Backend string `yaml:"backend"` // json (по умолчанию) | sqlite
+	Path    string `yaml:"path"`    // пусто — data/events.json или data/events.db
+	// ArchivePath — архив прошедших событий (тот же бэкенд);
+	// пусто — data/archive.json или data/archive.db
+	ArchivePath string `yaml:"archive_path"`
+	// RetentionDays — сколько дней хранить события в архиве; 0 — без ограничения
+	RetentionDays int `yaml:"retention_days"`
+}
+
+// MarketConfig — откуда брать свечи для реакции цены на прошедшие события.
+type MarketConfig struct {
+	Provider string  `yaml:"provider"` // bybit (по умолчанию) | binance | okx
+	BaseURL  string  `yaml:"base_url"` // пусто — боевой адрес API
+	Quote    string  `yaml:"quote"`    // котируемая валюта пары; пусто — USDT
+	RPS      float64 `yaml:"rps"`      // лимит запросов в секунду; 0 — по умолчанию провайдера
+}
+
+// TokenomicsConfig — провайдер токеномики для обогащения событий.
+type TokenomicsConfig struct {
+	Provider string `yaml:"provider"` // coingecko (по умолчанию) | static | none
+	BaseURL  string `yaml:"base_url"` // coingecko: пусто — публичный API
+	APIKey   string `yaml:"api_key"`  // coingecko: демо- или pro-ключ, необязателен
+	File     string `yaml:"file"`     // static: YAML с токеномикой по тикерам
+	// TTLHours — сколько часов считать ответ провайдера свежим (0 — 6)
+	TTLHours int `yaml:"ttl_hours"`
+}
+
+// TTL возвращает время жизни ответа провайдера с учётом значения по умолчанию.
+func (t TokenomicsConfig) TTL() time.Duration {
+	if t.TTLHours <= 0 {
+		return 6 * time.Hour
+	}
+	return time.Duration(t.TTLHours) * time.Hour
+}
+
+// StrategiesConfig — откуда брать стратегии и как часто проверять файл.
+type StrategiesConfig struct {
+	// File — YAML со стратегиями; пусто — встроенные
+	// (internal/strategy/strategies.yaml). Относительный путь — от каталога
+	// config.yaml. Отсутствующий файл создаётся из встроенных
+	File string `yaml:"file"`
+	// ReloadSeconds — как часто проверять, не изменился ли файл (0 — 30)
+	ReloadSeconds int `yaml:"reload_seconds"`
+}
+
+// ReloadInterval возвращает период проверки файла стратегий.
+func (s StrategiesConfig) ReloadInterval() time.Duration {
+	if s.ReloadSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(s.ReloadSeconds) * time.Second
+}
+
+// SourcesConfig — настройки источников по имени сканера (binance, bybit, ...).
+// Имя должно совпадать с тем, под которым сканер зарегистрирован в пакете scanner.
+type SourcesConfig map[string]SourceConfig
+
+// SourceConfig — настройки одного источника.
+// В YAML допускается короткая форма `binance: true`.
+type SourceConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	BaseURL string   `yaml:"base_url"` // переопределить адрес API (прокси, стенд); пусто — боевой
+	Mirrors []string `yaml:"mirrors"`  // запасные хосты с тем же API, пробуются по порядку
+	// MaxPages — сколько страниц ленты анонсов листать максимум (0 — по умолчанию)
+	MaxPages int `yaml:"max_pages"`
+	// FetchDetails — догружать текст новых анонсов, чтобы найти точное время
+	// начала торгов (каждый анонс качается один раз)
+	FetchDetails bool `yaml:"fetch_details"`
+}
+
+// UnmarshalYAML принимает и короткую форму (bool), и развёрнутую (mapping).
+// В развёрнутой форме источник считается включённым, если enabled не указан.
+func (s *SourceConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&s.Enabled)
+	}
+	type plain SourceConfig
+	p := plain{Enabled: true}
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*s = SourceConfig(p)
+	return nil
 }
 
 func Load(path string) (*Config, error) {
@@ -48,5 +156,67 @@ func Load(path string) (*Config, error) {
 	if cfg.Scanner.RefreshIntervalMinutes == 0 {
 		cfg.Scanner.RefreshIntervalMinutes = 60
 	}
+	if cfg.Scanner.FailureAlertAfter == 0 {
+		cfg.Scanner.FailureAlertAfter = 3
+	}
+	switch cfg.Storage.Backend {
+	case "", "json":
+		cfg.Storage.Backend = "json"
+		if cfg.Storage.Path == "" {
+			cfg.Storage.Path = "data/events.json"
+		}
+		if cfg.Storage.ArchivePath == "" {
+			cfg.Storage.ArchivePath = "data/archive.json"
+		}
+	case "sqlite":
+		if cfg.Storage.Path == "" {
+			cfg.Storage.Path = "data/events.db"
+		}
+		if cfg.Storage.ArchivePath == "" {
+			cfg.Storage.ArchivePath = "data/archive.db"
+		}
+	default:
+		return nil, fmt.Errorf("storage.backend: unknown backend %q (json, sqlite)", cfg.Storage.Backend)
+	}
+	if cfg.Storage.RetentionDays < 0 {
+		return nil, fmt.Errorf("storage.retention_days: must not be negative")
+	}
+	if cfg.Storage.ArchivePath == cfg.Storage.Path {
+		return nil, fmt.Errorf("storage.archive_path: must differ from storage.path")
+	}
+	if err := cfg.Merge.validate(cfg.Sources); err != nil {
+		return nil, err
+	}
+	if err := cfg.Unlocks.validate(cfg.Windows); err != nil {
+		return nil, err
+	}
+	switch cfg.Market.Provider {
+	case "":
+		cfg.Market.Provider = "bybit"
+	case "bybit", "binance", "okx":
+	default:
+		return nil, fmt.Errorf("market.provider: unknown provider %q (bybit, binance, okx)", cfg.Market.Provider)
+	}
+	if cfg.Market.RPS < 0 {
+		return nil, fmt.Errorf("market.rps: must not be negative")
+	}
+	switch cfg.Tokenomics.Provider {
+	case "":
+		cfg.Tokenomics.Provider = "coingecko"
+	case "coingecko", "none":
+	case "static":
+		if cfg.Tokenomics.File == "" {
+			return nil, fmt.Errorf("tokenomics.file: required for the static provider")
+		}
+	default:
+		return nil, fmt.Errorf("tokenomics.provider: unknown provider %q (coingecko, static, none)", cfg.Tokenomics.Provider)
+	}
+	if cfg.Market.Quote == "" {
+		cfg.Market.Quote = "USDT"
+	}
+	// Файл стратегий лежит рядом с config.yaml, откуда бы ни запускали бота
+	if f := cfg.Strategies.File; f != "" && !filepath.IsAbs(f) {
+		cfg.Strategies.File = filepath.Join(filepath.Dir(path), f)
+	}
 	return &cfg, nil
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadResolvesStrategiesFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"strategies.yaml", filepath.Join(dir, "strategies.yaml")},
+		{"conf.d/strategies.yaml", filepath.Join(dir, "conf.d", "strategies.yaml")},
+		{"/etc/bot/strategies.yaml", "/etc/bot/strategies.yaml"},
+		{"", ""}, // встроенные стратегии
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte("strategies:\n  file: \""+tt.file+"\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Strategies.File != tt.want {
+			t.Errorf("file %q resolved to %q, want %q", tt.file, cfg.Strategies.File, tt.want)
+		}
+	}
+}
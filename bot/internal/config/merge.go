@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MergeConfig — как сливать одно событие из разных источников.
+// Priority — порядок источников: первый даёт основные поля сводного события.
+// Fields переопределяет источник отдельных полей списком предпочтений;
+// источник без значения поля пропускается, а если не подошёл никто —
+// действует Priority.
+//
+//	merge:
+//	  priority: [bybit, binance, okx, tokenunlocks, airdrops]
+//	  fields:
+//	    date: [exact, bybit]     # точное время, иначе время Bybit
+//	    details: [tokenunlocks]
+type MergeConfig struct {
+	Priority []string            `yaml:"priority"`
+	Fields   map[string][]string `yaml:"fields"`
+}
+
+// Поля сводного события, для которых можно задать свой источник
+const (
+	MergeFieldDate    = "date" // EventAt вместе с точностью и датой анонса
+	MergeFieldTitle   = "title"
+	MergeFieldDetails = "details"
+	MergeFieldURL     = "url"
+)
+
+// Особые значения в списке предпочтений поля date
+const (
+	MergeExact    = "exact"    // источник, знающий точное время
+	MergeEarliest = "earliest" // самое раннее время среди источников
+)
+
+// defaultPriority — порядок по умолчанию, если merge.priority не задан
+var defaultPriority = []string{"binance", "bybit", "okx", "tokenunlocks", "airdrops"}
+
+// SourcePriority возвращает порядок источников с учётом значения по умолчанию.
+func (m MergeConfig) SourcePriority() []string {
+	if len(m.Priority) == 0 {
+		return defaultPriority
+	}
+	return m.Priority
+}
+
+// validate проверяет правила слияния против списка источников из sources.
+func (m MergeConfig) validate(sources SourcesConfig) error {
+	known := func(name string) error {
+		if _, ok := sources[name]; !ok {
+			return fmt.Errorf("unknown source %q (not listed in sources)", name)
+		}
+		return nil
+	}
+	for i, name := range m.Priority {
+		if err := known(name); err != nil {
+			return fmt.Errorf("merge.priority: %w", err)
+		}
+		if slices.Contains(m.Priority[:i], name) {
+			return fmt.Errorf("merge.priority: duplicate source %q", name)
+		}
+	}
+	for field, prefs := range m.Fields {
+		switch field {
+		case MergeFieldDate, MergeFieldTitle, MergeFieldDetails, MergeFieldURL:
+		default:
+			return fmt.Errorf("merge.fields: unknown field %q (date, title, details, url)", field)
+		}
+		if len(prefs) == 0 {
+			return fmt.Errorf("merge.fields.%s: empty list", field)
+		}
+		for i, name := range prefs {
+			if slices.Contains(prefs[:i], name) {
+				return fmt.Errorf("merge.fields.%s: duplicate %q", field, name)
+			}
+			if name == MergeExact || name == MergeEarliest {
+				if field != MergeFieldDate {
+					return fmt.Errorf("merge.fields.%s: %q applies only to date", field, name)
+				}
+				continue
+			}
+			if err := known(name); err != nil {
+				return fmt.Errorf("merge.fields.%s: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// UnlocksConfig — уровни серьёзности разлоков. Уровни перечисляются от
+// самого серьёзного; разлок получает первый, чей порог он превышает по доле
+// предложения или по сумме. Последний уровень — без порогов, для всех
+// остальных разлоков (и тех, чей размер неизвестен).
+//
+//	unlocks:
+//	  tiers:
+//	    - {name: major, icon: 🔴, min_percent: 5, min_value_usd: 50000000, digest: true, alert_24h: true}
+//	    - {name: minor, icon: ⚪}
+//	  schedule_days: 30
+type UnlocksConfig struct {
+	Tiers []UnlockTier `yaml:"tiers"`
+	// ScheduleDays — на сколько дней вперёд показывать график разлоков
+	// (/unlock); 0 — на весь горизонт окна разлоков, больше него нельзя:
+	// дальше источник не опрашивается
+	ScheduleDays int `yaml:"schedule_days"`
+}
+
+// unlocksSource — источник расписания разлоков (сканер tokenunlocks)
+const unlocksSource = "tokenunlocks"
+
+// ScheduleHorizon возвращает, на сколько вперёд строить график разлоков.
+func (u UnlocksConfig) ScheduleHorizon(windows WindowsConfig) time.Duration {
+	if u.ScheduleDays > 0 {
+		return days(u.ScheduleDays)
+	}
+	return windows.For(unlocksSource, string(model.EventUnlock)).Horizon
+}
+
+// ScanHorizon возвращает, на сколько вперёд источник собирает разлоки:
+// о более дальних календарь не знает.
+func (u UnlocksConfig) ScanHorizon(windows WindowsConfig) time.Duration {
+	return windows.ForSource(unlocksSource).Horizon
+}
+
+// UnlockTier — один уровень разлоков и что с такими разлоками делать.
+type UnlockTier struct {
+	Name        string  `yaml:"name"`
+	Icon        string  `yaml:"icon"`          // значок в дайджесте и списках
+	MinPercent  float64 `yaml:"min_percent"`   // % общего предложения; 0 — порог не задан
+	MinValueUSD float64 `yaml:"min_value_usd"` // USD; 0 — порог не задан
+	Digest      bool    `yaml:"digest"`        // показывать в еженедельном дайджесте
+	Alert24h    bool    `yaml:"alert_24h"`     // присылать алерт за 24ч
+}
+
+// defaultUnlockTiers — уровни, если unlocks.tiers не задан
+var defaultUnlockTiers = []UnlockTier{
+	{Name: "major", Icon: "🔴", MinPercent: 5, MinValueUSD: 50e6, Digest: true, Alert24h: true},
+	{Name: "notable", Icon: "🟠", MinPercent: 1, MinValueUSD: 10e6, Digest: true},
+	{Name: "minor", Icon: "⚪"},
+}
+
+// TierList возвращает уровни с учётом значения по умолчанию.
+func (u UnlocksConfig) TierList() []UnlockTier {
+	if len(u.Tiers) == 0 {
+		return defaultUnlockTiers
+	}
+	return u.Tiers
+}
+
+// TierFor возвращает уровень разлока и его номер в TierList; nil — размер
+// неизвестен, такой разлок попадает в последний уровень.
+func (u UnlocksConfig) TierFor(size *model.Unlock) (UnlockTier, int) {
+	tiers := u.TierList()
+	if size != nil {
+		for i, t := range tiers[:len(tiers)-1] {
+			if (t.MinPercent > 0 && size.Percent >= t.MinPercent) ||
+				(t.MinValueUSD > 0 && size.ValueUSD >= t.MinValueUSD) {
+				return t, i
+			}
+		}
+	}
+	last := len(tiers) - 1
+	return tiers[last], last
+}
+
+// validate проверяет уровни (имена, пороги, последний уровень без порогов)
+// и горизонт графика против окна разлоков.
+func (u UnlocksConfig) validate(windows WindowsConfig) error {
+	if u.ScheduleDays < 0 {
+		return fmt.Errorf("unlocks.schedule_days: must not be negative")
+	}
+	if window := windows.For(unlocksSource, string(model.EventUnlock)).Horizon; days(u.ScheduleDays) > window {
+		return fmt.Errorf("unlocks.schedule_days: %d is beyond the unlock window (%d days, see windows)",
+			u.ScheduleDays, int(window/(24*time.Hour)))
+	}
+	for i, t := range u.Tiers {
+		switch {
+		case t.Name == "":
+			return fmt.Errorf("unlocks.tiers[%d]: name is required", i)
+		case slices.ContainsFunc(u.Tiers[:i], func(o UnlockTier) bool { return o.Name == t.Name }):
+			return fmt.Errorf("unlocks.tiers: duplicate tier %q", t.Name)
+		case t.MinPercent < 0 || t.MinValueUSD < 0:
+			return fmt.Errorf("unlocks.tiers.%s: thresholds must not be negative", t.Name)
+		case i == len(u.Tiers)-1 && (t.MinPercent > 0 || t.MinValueUSD > 0):
+			return fmt.Errorf("unlocks.tiers.%s: the last tier takes all remaining unlocks and has no thresholds", t.Name)
+		case i < len(u.Tiers)-1 && t.MinPercent == 0 && t.MinValueUSD == 0:
+			return fmt.Errorf("unlocks.tiers.%s: set min_percent or min_value_usd (only the last tier has none)", t.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"crypto-bot/internal/model"
+)
+
+func TestTierFor(t *testing.T) {
+	custom := UnlocksConfig{Tiers: []UnlockTier{
+		{Name: "usd", MinValueUSD: 1e6},
+		{Name: "share", MinPercent: 2},
+		{Name: "rest"},
+	}}
+	tests := []struct {
+		name    string
+		unlocks UnlocksConfig
+		size    *model.Unlock
+		want    string
+		wantN   int
+	}{
+		{"unknown size goes last", UnlocksConfig{}, nil, "minor", 2},
+		{"zero size", UnlocksConfig{}, &model.Unlock{}, "minor", 2},
+		{"percent alone", UnlocksConfig{}, &model.Unlock{Percent: 6, ValueUSD: 1e6}, "major", 0},
+		{"usd alone", UnlocksConfig{}, &model.Unlock{Percent: 0.1, ValueUSD: 60e6}, "major", 0},
+		{"threshold inclusive", UnlocksConfig{}, &model.Unlock{Percent: 1}, "notable", 1},
+		{"below both", UnlocksConfig{}, &model.Unlock{Percent: 0.9, ValueUSD: 9e6}, "minor", 2},
+		// Порог 0 не задан, а не "любой размер"
+		{"unset usd threshold", custom, &model.Unlock{Percent: 3}, "share", 1},
+		{"unset percent threshold", custom, &model.Unlock{Percent: 50, ValueUSD: 2e6}, "usd", 0},
+		{"custom last", custom, &model.Unlock{Percent: 1, ValueUSD: 5e5}, "rest", 2},
+	}
+	for _, tt := range tests {
+		tier, n := tt.unlocks.TierFor(tt.size)
+		if tier.Name != tt.want || n != tt.wantN {
+			t.Errorf("%s: TierFor = %s (%d), want %s (%d)", tt.name, tier.Name, n, tt.want, tt.wantN)
+		}
+	}
+}
+
+func TestUnlocksValidate(t *testing.T) {
+	windows := WindowsConfig{Types: map[string]WindowConfig{"unlock": {HorizonDays: intp(45)}}}
+	tiers := func(t ...UnlockTier) []UnlockTier { return t }
+	tests := []struct {
+		name    string
+		unlocks UnlocksConfig
+		wantErr string // "" — конфиг верный
+	}{
+		{"defaults", UnlocksConfig{}, ""},
+		{"single tier", UnlocksConfig{Tiers: tiers(UnlockTier{Name: "all"})}, ""},
+		{"schedule within window", UnlocksConfig{ScheduleDays: 45}, ""},
+		{"schedule beyond window", UnlocksConfig{ScheduleDays: 46}, "beyond the unlock window"},
+		{"negative schedule", UnlocksConfig{ScheduleDays: -1}, "must not be negative"},
+		{"no name", UnlocksConfig{Tiers: tiers(UnlockTier{MinPercent: 1}, UnlockTier{Name: "rest"})}, "name is required"},
+		{"duplicate name", UnlocksConfig{Tiers: tiers(
+			UnlockTier{Name: "big", MinPercent: 5}, UnlockTier{Name: "big", MinPercent: 1}, UnlockTier{Name: "rest"},
+		)}, "duplicate tier"},
+		{"negative percent", UnlocksConfig{Tiers: tiers(UnlockTier{Name: "big", MinPercent: -1, MinValueUSD: 1e6}, UnlockTier{Name: "rest"})},
+			"must not be negative"},
+		{"negative usd", UnlocksConfig{Tiers: tiers(UnlockTier{Name: "big", MinPercent: 1, MinValueUSD: -1}, UnlockTier{Name: "rest"})},
+			"must not be negative"},
+		{"last tier with threshold", UnlocksConfig{Tiers: tiers(UnlockTier{Name: "big", MinPercent: 5}, UnlockTier{Name: "rest", MinValueUSD: 1e6})},
+			"has no thresholds"},
+		{"middle tier without threshold", UnlocksConfig{Tiers: tiers(UnlockTier{Name: "big"}, UnlockTier{Name: "rest"})},
+			"set min_percent or min_value_usd"},
+	}
+	for _, tt := range tests {
+		err := tt.unlocks.validate(windows)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import "time"
+
+// WindowsConfig — политика временных окон: какие события сканеры собирают,
+// а календарь показывает. Переопределения применяются поверх default:
+// сначала по источнику, затем по типу события (тип важнее).
+type WindowsConfig struct {
+	Default WindowConfig            `yaml:"default"`
+	Sources map[string]WindowConfig `yaml:"sources"` // по имени источника: tokenunlocks, binance, ...
+	Types   map[string]WindowConfig `yaml:"types"`   // по типу события: unlock, listing, ...
+
+	// PruneAfterHours — через сколько часов после события переносить его из кэша в архив
+	PruneAfterHours int `yaml:"prune_after_hours"`
+}
+
+// WindowConfig — окно в днях. nil-поле наследуется от более общего уровня,
+// поэтому явный 0 (напр. lookback_days: 0 для разлоков) допустим.
+type WindowConfig struct {
+	LookbackDays *int `yaml:"lookback_days"`
+	HorizonDays  *int `yaml:"horizon_days"`
+}
+
+// Window — окно вокруг текущего момента: [now-Lookback, now+Horizon].
+type Window struct {
+	Lookback time.Duration
+	Horizon  time.Duration
+}
+
+// Range возвращает границы окна относительно now.
+func (w Window) Range(now time.Time) (from, to time.Time) {
+	return now.Add(-w.Lookback), now.Add(w.Horizon)
+}
+
+// Contains сообщает, попадает ли t в окно относительно now (границы исключены).
+func (w Window) Contains(now, t time.Time) bool {
+	from, to := w.Range(now)
+	return t.After(from) && t.Before(to)
+}
+
+const (
+	defaultLookbackDays    = 14 // биржи анонсируют за 7-14 дней
+	defaultHorizonDays     = 7
+	defaultPruneAfterHours = 48
+)
+
+// ForSource возвращает окно сканирования источника: default + переопределение источника.
+func (c WindowsConfig) ForSource(source string) Window {
+	w := c.Default.apply(Window{
+		Lookback: days(defaultLookbackDays),
+		Horizon:  days(defaultHorizonDays),
+	})
+	if o, ok := c.Sources[source]; ok {
+		w = o.apply(w)
+	}
+	return w
+}
+
+// For возвращает окно для события данного источника и типа.
+func (c WindowsConfig) For(source, eventType string) Window {
+	w := c.ForSource(source)
+	if o, ok := c.Types[eventType]; ok {
+		w = o.apply(w)
+	}
+	return w
+}
+
+// MaxHorizon — самый дальний горизонт среди всех окон (default, источники,
+// типы и их сочетания): до него календарь показывает события вперёд.
+func (c WindowsConfig) MaxHorizon() time.Duration {
+	sources := []string{""}
+	for s := range c.Sources {
+		sources = append(sources, s)
+	}
+	h := c.ForSource("").Horizon
+	for _, s := range sources {
+		h = max(h, c.ForSource(s).Horizon)
+		for t := range c.Types {
+			h = max(h, c.For(s, t).Horizon)
+		}
+	}
+	return h
+}
+
+// PruneAfter — сколько хранить событие в кэше после его даты.
+func (c WindowsConfig) PruneAfter() time.Duration {
+	if c.PruneAfterHours <= 0 {
+		return defaultPruneAfterHours * time.Hour
+	}
+	return time.Duration(c.PruneAfterHours) * time.Hour
+}
+
+func (o WindowConfig) apply(w Window) Window {
+	if o.LookbackDays != nil {
+		w.Lookback = days(*o.LookbackDays)
+	}
+	if o.HorizonDays != nil {
+		w.Horizon = days(*o.HorizonDays)
+	}
+	return w
+}
+
+func days(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func intp(n int) *int { return &n }
+
+func TestMaxHorizon(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  WindowsConfig
+		want time.Duration
+	}{
+		{"defaults", WindowsConfig{}, days(defaultHorizonDays)},
+		{"source", WindowsConfig{
+			Sources: map[string]WindowConfig{"tokenunlocks": {LookbackDays: intp(0), HorizonDays: intp(45)}},
+		}, days(45)},
+		{"type", WindowsConfig{
+			Types: map[string]WindowConfig{"unlock": {HorizonDays: intp(60)}},
+		}, days(60)},
+		{"shorter overrides", WindowsConfig{
+			Default: WindowConfig{HorizonDays: intp(10)},
+			Sources: map[string]WindowConfig{"airdrops": {HorizonDays: intp(3)}},
+		}, days(10)},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.MaxHorizon(); got != tt.want {
+			t.Errorf("%s: MaxHorizon() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package httpx
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Фикстуры — сырые ответы источников и бирж, сохранённые в файлы.
+// RecordingTransport пишет их во время живого прогона (scantest -record,
+// markettest -record). Обратно их отдают ReplayTransport — вместо сети в
+// http.Client — и StandIn — как HTTP-сервер на httptest, адрес которого
+// передаётся клиенту вместо боевого.
+
+// FixtureName возвращает имя файла фикстуры для запроса: путь для
+// читаемости плюс короткий хэш пути с query (query различает страницы,
+// каталоги и символы), напр. "v5_announcements_index-1a2b3c4d.body".
+// Хост в имя не входит — запись с боевого адреса находится и при запросе
+// к зеркалу или к стенду.
+func FixtureName(req *http.Request) string {
+	u := req.URL
+	readable := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, strings.Trim(u.Path, "/"))
+	sum := sha1.Sum([]byte(u.RequestURI()))
+	return fmt.Sprintf("%s-%s.body", readable, hex.EncodeToString(sum[:4]))
+}
+
+// RecordingTransport проксирует запросы в Next и сохраняет тела успешных
+// ответов в Dir под именем FixtureName.
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper // nil — http.DefaultTransport
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("record %s: read body: %w", req.URL, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("record %s: %w", req.URL, err)
+	}
+	if err := os.WriteFile(filepath.Join(t.Dir, FixtureName(req)), body, 0644); err != nil {
+		return nil, fmt.Errorf("record %s: %w", req.URL, err)
+	}
+	return resp, nil
+}
+
+// ReplayTransport отдаёт сохранённые фикстуры из Dir вместо похода в сеть.
+// Запрос без фикстуры — ошибка, чтобы тест не ушёл в сеть незаметно.
+type ReplayTransport struct {
+	Dir string
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := FixtureName(req)
+	body, err := os.ReadFile(filepath.Join(t.Dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: no fixture %s: %w", req.URL, name, err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// StandIn — http.Handler, отвечающий фикстурами из Dir со статусом 200.
+// Запрос без фикстуры — 404 с именем недостающего файла; ошибки бирж стенд
+// не воспроизводит (для них нужен свой handler).
+type StandIn struct {
+	Dir string
+}
+
+func (s StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := FixtureName(r)
+	body, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		http.Error(w, "no fixture "+name, http.StatusNotFound)
+		return
+	}
+	w.Write(body)
+}
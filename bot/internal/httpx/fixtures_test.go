@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFixtureNameIgnoresHost(t *testing.T) {
+	get := func(url string) string {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return FixtureName(req)
+	}
+	live := get("https://api.bybit.com/v5/market/kline?symbol=VANAUSDT&interval=60")
+	if stand := get("http://127.0.0.1:41234/v5/market/kline?symbol=VANAUSDT&interval=60"); stand != live {
+		t.Errorf("stand-in name %s, live %s", stand, live)
+	}
+	if other := get("https://api.bybit.com/v5/market/kline?symbol=BTCUSDT&interval=60"); other == live {
+		t.Errorf("different query, same name %s", live)
+	}
+	if want := "v5_market_kline-"; live[:len(want)] != want {
+		t.Errorf("name %s, want prefix %s", live, want)
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, `{"page":`+r.URL.Query().Get("page")+`}`)
+	}))
+	defer live.Close()
+	dir := t.TempDir()
+
+	get := func(rt http.RoundTripper, base, query string) (int, string) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: rt}).Get(base + "/feed?" + query)
+		if err != nil {
+			return 0, err.Error()
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	rec := &RecordingTransport{Dir: dir}
+	if code, body := get(rec, live.URL, "page=1"); code != http.StatusOK || body != `{"page":1}` {
+		t.Fatalf("record page 1: %d %s", code, body)
+	}
+	// Неуспешные ответы не записываются
+	if code, _ := get(rec, live.URL, "page=2"); code != http.StatusNotFound {
+		t.Fatalf("record page 2: %d", code)
+	}
+
+	replay := &ReplayTransport{Dir: dir}
+	if code, body := get(replay, "https://mirror.example", "page=1"); code != http.StatusOK || body != `{"page":1}` {
+		t.Errorf("replay page 1: %d %s", code, body)
+	}
+	if code, _ := get(replay, "https://mirror.example", "page=2"); code != 0 {
+		t.Errorf("replay page 2 without fixture: status %d, want error", code)
+	}
+
+	stand := httptest.NewServer(StandIn{Dir: dir})
+	defer stand.Close()
+	if code, body := get(nil, stand.URL, "page=1"); code != http.StatusOK || body != `{"page":1}` {
+		t.Errorf("stand-in page 1: %d %s", code, body)
+	}
+	if code, _ := get(nil, stand.URL, "page=2"); code != http.StatusNotFound {
+		t.Errorf("stand-in page 2: %d, want 404", code)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30":                            30 * time.Second,
+		" 5 ":                           5 * time.Second,
+		"":                              0,
+		"0":                             0,
+		"-1":                            0,
+		"Wed, 21 Oct 2026 07:28:00 GMT": 0,
+	}
+	for in, want := range tests {
+		if got := RetryAfter(in); got != want {
+			t.Errorf("RetryAfter(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Package httpx — общее HTTP-хозяйство сканеров анонсов и клиентов рыночных
+// данных: разбор Retry-After и фикстуры для офлайн-тестов.
+package httpx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfter разбирает Retry-After в секундах; дату и мусор игнорирует.
+func RetryAfter(v string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,215 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	binanceSpotURL    = "https://api.binance.com"
+	binanceFuturesURL = "https://fapi.binance.com"
+	binanceRPS        = 10 // лимит — 6000 единиц веса в минуту на IP, берём с запасом
+	binanceKlines     = 1000
+
+	binanceCodeRateLimit = -1003
+	binanceCodeBadSymbol = -1121 // "Invalid symbol."
+)
+
+type binanceClient struct {
+	api
+	spot, futures string // базовые адреса спота и USDT-фьючерсов
+}
+
+func newBinance(opts Options) *binanceClient {
+	return &binanceClient{
+		api:     newAPI(ProviderBinance, opts, binanceRPS),
+		spot:    opts.base(binanceSpotURL),
+		futures: opts.base(binanceFuturesURL),
+	}
+}
+
+func (c *binanceClient) Name() string { return ProviderBinance }
+
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// call запрашивает path у спота или фьючерсов и разбирает ответ в v
+func (c *binanceClient) call(ctx context.Context, cat Category, path string, v any) error {
+	var base string
+	switch cat {
+	case Spot:
+		base = c.spot + "/api/v3"
+	case Perp:
+		base = c.futures + "/fapi/v1"
+	default:
+		return fmt.Errorf("binance category %q: %w", cat, ErrUnsupported)
+	}
+	body, status, err := c.get(ctx, base+path)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		e := &APIError{Provider: ProviderBinance, Status: status}
+		var be binanceError
+		if json.Unmarshal(body, &be) == nil && be.Code != 0 {
+			e.Code, e.Message = strconv.Itoa(be.Code), be.Msg
+			switch be.Code {
+			case binanceCodeBadSymbol:
+				e.kind = ErrUnknownSymbol
+			case binanceCodeRateLimit:
+				e.kind = ErrRateLimited
+			}
+		}
+		return e
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode binance response: %w", err)
+	}
+	return nil
+}
+
+func binanceSymbol(p Pair) string { return p.Base + p.Quote }
+
+func (c *binanceClient) Klines(ctx context.Context, q KlineQuery) ([]Kline, error) {
+	if q.Interval.Duration() == 0 {
+		return nil, fmt.Errorf("binance interval %q: %w", q.Interval, ErrUnsupported)
+	}
+	return klinePages(q, binanceKlines, func(from, to time.Time) ([]Kline, error) {
+		// [openTime ms, "open", "high", "low", "close", "volume", closeTime, "quoteVolume", ...]
+		var rows [][]any
+		path := fmt.Sprintf("/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=%d",
+			binanceSymbol(q.Pair), q.Interval, from.UnixMilli(), to.UnixMilli()-1, binanceKlines)
+		if err := c.call(ctx, q.Category, path, &rows); err != nil {
+			return nil, err
+		}
+		out := make([]Kline, 0, len(rows))
+		for _, row := range rows {
+			k, err := parseBinanceKline(row)
+			if err != nil {
+				return nil, fmt.Errorf("binance kline: %w", err)
+			}
+			out = append(out, k)
+		}
+		return out, nil
+	})
+}
+
+func parseBinanceKline(row []any) (Kline, error) {
+	if len(row) < 8 {
+		return Kline{}, fmt.Errorf("short row %v", row)
+	}
+	ms, ok := row[0].(float64)
+	if !ok {
+		return Kline{}, fmt.Errorf("bad open time %v", row[0])
+	}
+	str := func(i int) string {
+		s, _ := row[i].(string)
+		return s
+	}
+	var p parser
+	k := Kline{
+		OpenTime: time.UnixMilli(int64(ms)).UTC(),
+		Open:     p.num(str(1)), High: p.num(str(2)), Low: p.num(str(3)), Close: p.num(str(4)),
+		Volume: p.num(str(5)), QuoteVolume: p.num(str(7)),
+	}
+	return k, p.err
+}
+
+func (c *binanceClient) Ticker(ctx context.Context, cat Category, p Pair) (Ticker, error) {
+	var t struct {
+		LastPrice          string `json:"lastPrice"`
+		HighPrice          string `json:"highPrice"`
+		LowPrice           string `json:"lowPrice"`
+		Volume             string `json:"volume"`
+		QuoteVolume        string `json:"quoteVolume"`
+		PriceChangePercent string `json:"priceChangePercent"`
+	}
+	if err := c.call(ctx, cat, "/ticker/24hr?symbol="+binanceSymbol(p), &t); err != nil {
+		return Ticker{}, err
+	}
+	var ps parser
+	out := Ticker{
+		Pair: p,
+		Last: ps.num(t.LastPrice), High: ps.num(t.HighPrice), Low: ps.num(t.LowPrice),
+		Volume: ps.num(t.Volume), QuoteVolume: ps.num(t.QuoteVolume),
+		ChangePct: ps.num(t.PriceChangePercent),
+	}
+	if ps.err != nil {
+		return Ticker{}, fmt.Errorf("binance ticker: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *binanceClient) Funding(ctx context.Context, p Pair) (Funding, error) {
+	var res struct {
+		LastFundingRate string `json:"lastFundingRate"`
+		NextFundingTime int64  `json:"nextFundingTime"`
+	}
+	if err := c.call(ctx, Perp, "/premiumIndex?symbol="+binanceSymbol(p), &res); err != nil {
+		return Funding{}, err
+	}
+	var ps parser
+	out := Funding{Pair: p, Rate: ps.num(res.LastFundingRate)}
+	if res.NextFundingTime > 0 {
+		out.NextAt = time.UnixMilli(res.NextFundingTime).UTC()
+	}
+	if ps.err != nil {
+		return Funding{}, fmt.Errorf("binance funding: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *binanceClient) OpenInterest(ctx context.Context, p Pair) (OpenInterest, error) {
+	var res struct {
+		OpenInterest string `json:"openInterest"` // в базовой валюте
+		Time         int64  `json:"time"`
+	}
+	if err := c.call(ctx, Perp, "/openInterest?symbol="+binanceSymbol(p), &res); err != nil {
+		return OpenInterest{}, err
+	}
+	var ps parser
+	out := OpenInterest{Pair: p, Amount: ps.num(res.OpenInterest), At: time.UnixMilli(res.Time).UTC()}
+	if ps.err != nil {
+		return OpenInterest{}, fmt.Errorf("binance open interest: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *binanceClient) Instruments(ctx context.Context, cat Category) ([]Instrument, error) {
+	var res struct {
+		Symbols []struct {
+			Symbol       string `json:"symbol"`
+			Status       string `json:"status"`
+			BaseAsset    string `json:"baseAsset"`
+			QuoteAsset   string `json:"quoteAsset"`
+			ContractType string `json:"contractType"` // только у фьючерсов
+			OnboardDate  int64  `json:"onboardDate"`  // только у фьючерсов, ms
+		} `json:"symbols"`
+	}
+	if err := c.call(ctx, cat, "/exchangeInfo", &res); err != nil {
+		return nil, err
+	}
+	out := make([]Instrument, 0, len(res.Symbols))
+	for _, s := range res.Symbols {
+		if cat == Perp && s.ContractType != "PERPETUAL" {
+			continue
+		}
+		it := Instrument{
+			Category: cat,
+			Pair:     NewPair(s.BaseAsset, s.QuoteAsset),
+			Symbol:   s.Symbol,
+			Trading:  s.Status == "TRADING",
+		}
+		if s.OnboardDate > 0 {
+			it.ListedAt = time.UnixMilli(s.OnboardDate).UTC()
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
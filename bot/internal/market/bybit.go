@@ -0,0 +1,275 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bybitBaseURL = "https://api.bybit.com"
+	bybitRPS     = 10 // публичный лимит — 600 запросов за 5 с на IP, берём с запасом
+	bybitKlines  = 1000
+
+	bybitCodeParams    = 10001 // ошибка параметров, в т.ч. неизвестный символ
+	bybitCodeRateLimit = 10006
+)
+
+type bybitClient struct {
+	api
+	base string
+}
+
+func newBybit(opts Options) *bybitClient {
+	return &bybitClient{api: newAPI(ProviderBybit, opts, bybitRPS), base: opts.base(bybitBaseURL)}
+}
+
+func (c *bybitClient) Name() string { return ProviderBybit }
+
+type bybitEnvelope struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// call запрашивает path и разбирает result в v
+func (c *bybitClient) call(ctx context.Context, path string, v any) error {
+	body, status, err := c.get(ctx, c.base+path)
+	if err != nil {
+		return err
+	}
+	// Bybit отвечает 403 при превышении лимита на IP
+	if status == http.StatusForbidden {
+		return &APIError{Provider: ProviderBybit, Status: status, kind: ErrRateLimited}
+	}
+	var env bybitEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		if status != http.StatusOK {
+			return &APIError{Provider: ProviderBybit, Status: status}
+		}
+		return fmt.Errorf("decode bybit response: %w", err)
+	}
+	if env.RetCode != 0 {
+		e := &APIError{Provider: ProviderBybit, Status: status, Code: strconv.Itoa(env.RetCode), Message: env.RetMsg}
+		switch env.RetCode {
+		case bybitCodeParams:
+			// Тот же код и у прочих ошибок параметров
+			if strings.Contains(strings.ToLower(env.RetMsg), "symbol") {
+				e.kind = ErrUnknownSymbol
+			}
+		case bybitCodeRateLimit:
+			e.kind = ErrRateLimited
+		}
+		return e
+	}
+	if err := json.Unmarshal(env.Result, v); err != nil {
+		return fmt.Errorf("decode bybit result: %w", err)
+	}
+	return nil
+}
+
+// bybitCategory — категория Bybit v5 для рынка
+func bybitCategory(cat Category) (string, error) {
+	switch cat {
+	case Spot:
+		return "spot", nil
+	case Perp:
+		return "linear", nil
+	}
+	return "", fmt.Errorf("bybit category %q: %w", cat, ErrUnsupported)
+}
+
+func bybitInterval(i Interval) (string, error) {
+	switch i {
+	case Minute1:
+		return "1", nil
+	case Minute5:
+		return "5", nil
+	case Minute15:
+		return "15", nil
+	case Hour1:
+		return "60", nil
+	case Hour4:
+		return "240", nil
+	case Day1:
+		return "D", nil
+	}
+	return "", fmt.Errorf("bybit interval %q: %w", i, ErrUnsupported)
+}
+
+func bybitSymbol(p Pair) string { return p.Base + p.Quote }
+
+func (c *bybitClient) Klines(ctx context.Context, q KlineQuery) ([]Kline, error) {
+	cat, err := bybitCategory(q.Category)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := bybitInterval(q.Interval)
+	if err != nil {
+		return nil, err
+	}
+	return klinePages(q, bybitKlines, func(from, to time.Time) ([]Kline, error) {
+		var res struct {
+			// [startTime ms, open, high, low, close, volume, turnover], новые — первыми
+			List [][]string `json:"list"`
+		}
+		// end у Bybit включительный
+		path := fmt.Sprintf("/v5/market/kline?category=%s&symbol=%s&interval=%s&start=%d&end=%d&limit=%d",
+			cat, bybitSymbol(q.Pair), interval, from.UnixMilli(), to.UnixMilli()-1, bybitKlines)
+		if err := c.call(ctx, path, &res); err != nil {
+			return nil, err
+		}
+		out := make([]Kline, 0, len(res.List))
+		for _, row := range res.List {
+			if len(row) < 7 {
+				return nil, fmt.Errorf("bybit kline: short row %v", row)
+			}
+			var p parser
+			out = append(out, Kline{
+				OpenTime: p.ms(row[0]),
+				Open:     p.num(row[1]), High: p.num(row[2]), Low: p.num(row[3]), Close: p.num(row[4]),
+				Volume: p.num(row[5]), QuoteVolume: p.num(row[6]),
+			})
+			if p.err != nil {
+				return nil, fmt.Errorf("bybit kline: %w", p.err)
+			}
+		}
+		return out, nil
+	})
+}
+
+type bybitTicker struct {
+	Symbol          string `json:"symbol"`
+	LastPrice       string `json:"lastPrice"`
+	HighPrice24h    string `json:"highPrice24h"`
+	LowPrice24h     string `json:"lowPrice24h"`
+	Volume24h       string `json:"volume24h"`
+	Turnover24h     string `json:"turnover24h"`
+	Price24hPcnt    string `json:"price24hPcnt"` // доля: 0.05 = +5%
+	FundingRate     string `json:"fundingRate"`
+	NextFundingTime string `json:"nextFundingTime"`
+}
+
+func (c *bybitClient) ticker(ctx context.Context, cat Category, p Pair) (bybitTicker, error) {
+	category, err := bybitCategory(cat)
+	if err != nil {
+		return bybitTicker{}, err
+	}
+	var res struct {
+		List []bybitTicker `json:"list"`
+	}
+	if err := c.call(ctx, fmt.Sprintf("/v5/market/tickers?category=%s&symbol=%s", category, bybitSymbol(p)), &res); err != nil {
+		return bybitTicker{}, err
+	}
+	if len(res.List) == 0 {
+		return bybitTicker{}, fmt.Errorf("bybit %s %s: %w", cat, p, ErrUnknownSymbol)
+	}
+	return res.List[0], nil
+}
+
+func (c *bybitClient) Ticker(ctx context.Context, cat Category, p Pair) (Ticker, error) {
+	t, err := c.ticker(ctx, cat, p)
+	if err != nil {
+		return Ticker{}, err
+	}
+	var ps parser
+	out := Ticker{
+		Pair: p,
+		Last: ps.num(t.LastPrice), High: ps.num(t.HighPrice24h), Low: ps.num(t.LowPrice24h),
+		Volume: ps.num(t.Volume24h), QuoteVolume: ps.num(t.Turnover24h),
+		ChangePct: ps.num(t.Price24hPcnt) * 100,
+	}
+	if ps.err != nil {
+		return Ticker{}, fmt.Errorf("bybit ticker: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *bybitClient) Funding(ctx context.Context, p Pair) (Funding, error) {
+	t, err := c.ticker(ctx, Perp, p)
+	if err != nil {
+		return Funding{}, err
+	}
+	var ps parser
+	out := Funding{Pair: p, Rate: ps.num(t.FundingRate), NextAt: ps.ms(t.NextFundingTime)}
+	if ps.err != nil {
+		return Funding{}, fmt.Errorf("bybit funding: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *bybitClient) OpenInterest(ctx context.Context, p Pair) (OpenInterest, error) {
+	var res struct {
+		List []struct {
+			OpenInterest string `json:"openInterest"` // в базовой валюте
+			Timestamp    string `json:"timestamp"`
+		} `json:"list"`
+	}
+	path := fmt.Sprintf("/v5/market/open-interest?category=linear&symbol=%s&intervalTime=5min&limit=1", bybitSymbol(p))
+	if err := c.call(ctx, path, &res); err != nil {
+		return OpenInterest{}, err
+	}
+	if len(res.List) == 0 {
+		return OpenInterest{}, fmt.Errorf("bybit open interest %s: %w", p, ErrUnknownSymbol)
+	}
+	var ps parser
+	out := OpenInterest{Pair: p, Amount: ps.num(res.List[0].OpenInterest), At: ps.ms(res.List[0].Timestamp)}
+	if ps.err != nil {
+		return OpenInterest{}, fmt.Errorf("bybit open interest: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *bybitClient) Instruments(ctx context.Context, cat Category) ([]Instrument, error) {
+	category, err := bybitCategory(cat)
+	if err != nil {
+		return nil, err
+	}
+	var out []Instrument
+	cursor := ""
+	for {
+		var res struct {
+			List []struct {
+				Symbol       string `json:"symbol"`
+				BaseCoin     string `json:"baseCoin"`
+				QuoteCoin    string `json:"quoteCoin"`
+				Status       string `json:"status"`
+				ContractType string `json:"contractType"` // у linear: LinearPerpetual | LinearFutures
+				LaunchTime   string `json:"launchTime"`
+			} `json:"list"`
+			NextPageCursor string `json:"nextPageCursor"`
+		}
+		path := fmt.Sprintf("/v5/market/instruments-info?category=%s&limit=1000", category)
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		if err := c.call(ctx, path, &res); err != nil {
+			return nil, err
+		}
+		for _, it := range res.List {
+			if cat == Perp && it.ContractType != "LinearPerpetual" {
+				continue
+			}
+			var ps parser
+			out = append(out, Instrument{
+				Category: cat,
+				Pair:     NewPair(it.BaseCoin, it.QuoteCoin),
+				Symbol:   it.Symbol,
+				Trading:  it.Status == "Trading",
+				ListedAt: ps.ms(it.LaunchTime),
+			})
+			if ps.err != nil {
+				return nil, fmt.Errorf("bybit instrument %s: %w", it.Symbol, ps.err)
+			}
+		}
+		if res.NextPageCursor == "" || res.NextPageCursor == cursor || len(res.List) == 0 {
+			return out, nil
+		}
+		cursor = res.NextPageCursor
+	}
+}
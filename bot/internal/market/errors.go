@@ -0,0 +1,48 @@
+package market
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Классы ошибок Client — для errors.Is
+var (
+	ErrUnknownSymbol = errors.New("unknown symbol")          // биржа не торгует такой парой
+	ErrRateLimited   = errors.New("rate limited")            // превышен лимит запросов
+	ErrUnsupported   = errors.New("not supported by market") // напр. фандинг у спота
+)
+
+// APIError — отказ биржи: HTTP-статус и код ошибки из тела ответа
+type APIError struct {
+	Provider   string
+	Status     int    // HTTP-статус (у Bybit и OKX ошибки приходят и со статусом 200)
+	Code       string // код ошибки биржи; пусто — не прислан
+	Message    string
+	RetryAfter time.Duration // из Retry-After при ErrRateLimited
+
+	kind error // класс ошибки или nil
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Provider)
+	if e.Status != 0 && e.Status != http.StatusOK {
+		fmt.Fprintf(&sb, ": status %d", e.Status)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&sb, ": code %s", e.Code)
+	}
+	if e.Message != "" {
+		sb.WriteString(": " + e.Message)
+	}
+	if e.kind != nil {
+		fmt.Fprintf(&sb, " (%v)", e.kind)
+	}
+	return sb.String()
+}
+
+// Unwrap даёт errors.Is сравнить ошибку с ErrUnknownSymbol и др.
+func (e *APIError) Unwrap() error { return e.kind }
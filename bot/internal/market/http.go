@@ -0,0 +1,93 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"crypto-bot/internal/httpx"
+)
+
+const (
+	requestTimeout = 15 * time.Second
+	maxBody        = 16 << 20 // 16 MB cap: списки инструментов Binance — несколько МБ
+)
+
+// api — HTTP-доступ клиента биржи с общим лимитом запросов
+type api struct {
+	provider string
+	client   *http.Client
+	limit    *limiter
+}
+
+func newAPI(provider string, opts Options, defRPS float64) api {
+	return api{
+		provider: provider,
+		client:   &http.Client{Timeout: requestTimeout, Transport: opts.Transport},
+		limit:    newLimiter(opts.RPS, defRPS),
+	}
+}
+
+// get выполняет GET и возвращает тело и HTTP-статус. Тело читается и при
+// неуспешном статусе — биржи кладут туда код ошибки; разбирает его клиент.
+// 429 (и 418 — бан IP у Binance) сразу становятся ErrRateLimited.
+func (a api) get(ctx context.Context, url string) ([]byte, int, error) {
+	if err := a.limit.wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		return nil, resp.StatusCode, &APIError{
+			Provider:   a.provider,
+			Status:     resp.StatusCode,
+			RetryAfter: httpx.RetryAfter(resp.Header.Get("Retry-After")),
+			kind:       ErrRateLimited,
+		}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("read body from %s: %w", url, err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// parser разбирает строковые поля ответа биржи и запоминает первую ошибку —
+// чтобы не проверять каждое поле отдельно
+type parser struct{ err error }
+
+// num — число; пустая строка — 0
+func (p *parser) num(s string) float64 {
+	if s == "" || p.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.err = fmt.Errorf("parse number %q: %w", s, err)
+	}
+	return v
+}
+
+// ms — время из миллисекунд Unix; пустая строка и "0" — нулевое время
+func (p *parser) ms(s string) time.Time {
+	if s == "" || s == "0" || p.err != nil {
+		return time.Time{}
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		p.err = fmt.Errorf("parse time %q: %w", s, err)
+		return time.Time{}
+	}
+	return time.UnixMilli(v).UTC()
+}
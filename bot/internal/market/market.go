@@ -0,0 +1,234 @@
+// Package market — публичные рыночные данные бирж (свечи, тикеры, фандинг,
+// открытый интерес, списки инструментов) за общим интерфейсом Client.
+// Ключи API не нужны. Реализации: Bybit v5, Binance, OKX.
+package market
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client — публичный API одной биржи. Ошибки — *APIError и ошибки сети;
+// классы ошибок проверяются через errors.Is (ErrUnknownSymbol, ErrRateLimited,
+// ErrUnsupported).
+type Client interface {
+	// Name — имя провайдера: bybit | binance | okx
+	Name() string
+	// Klines возвращает свечи, открытые в [q.From, q.To), по возрастанию времени.
+	Klines(ctx context.Context, q KlineQuery) ([]Kline, error)
+	// Ticker возвращает статистику пары за 24 часа.
+	Ticker(ctx context.Context, cat Category, p Pair) (Ticker, error)
+	// Funding возвращает текущую ставку фандинга бессрочного контракта.
+	Funding(ctx context.Context, p Pair) (Funding, error)
+	// OpenInterest возвращает открытый интерес бессрочного контракта.
+	OpenInterest(ctx context.Context, p Pair) (OpenInterest, error)
+	// Instruments возвращает все инструменты рынка cat.
+	Instruments(ctx context.Context, cat Category) ([]Instrument, error)
+}
+
+// Category — рынок инструмента
+type Category string
+
+const (
+	Spot Category = "spot"
+	Perp Category = "perp" // бессрочные фьючерсы (USDT-маржинальные)
+)
+
+// Pair — торговая пара; у каждой биржи свой формат символа
+type Pair struct {
+	Base  string // напр. VANA
+	Quote string // напр. USDT
+}
+
+// NewPair строит пару из тикера токена и котируемой валюты
+func NewPair(base, quote string) Pair {
+	return Pair{Base: strings.ToUpper(base), Quote: strings.ToUpper(quote)}
+}
+
+func (p Pair) String() string { return p.Base + "/" + p.Quote }
+
+// Interval — длительность свечи
+type Interval string
+
+const (
+	Minute1  Interval = "1m"
+	Minute5  Interval = "5m"
+	Minute15 Interval = "15m"
+	Hour1    Interval = "1h"
+	Hour4    Interval = "4h"
+	Day1     Interval = "1d"
+)
+
+// Duration возвращает длительность свечи; 0 — неизвестный интервал
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Minute1:
+		return time.Minute
+	case Minute5:
+		return 5 * time.Minute
+	case Minute15:
+		return 15 * time.Minute
+	case Hour1:
+		return time.Hour
+	case Hour4:
+		return 4 * time.Hour
+	case Day1:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// KlineQuery — какие свечи запросить
+type KlineQuery struct {
+	Category Category
+	Pair     Pair
+	Interval Interval
+	From, To time.Time // время открытия свечи в [From, To)
+}
+
+// Kline — одна свеча
+type Kline struct {
+	OpenTime               time.Time // начало свечи (UTC)
+	Open, High, Low, Close float64
+	Volume                 float64 // объём в базовой валюте
+	QuoteVolume            float64 // объём в котируемой валюте
+}
+
+// Ticker — статистика пары за 24 часа
+type Ticker struct {
+	Pair        Pair
+	Last        float64
+	High, Low   float64
+	Volume      float64 // в базовой валюте
+	QuoteVolume float64 // в котируемой валюте
+	ChangePct   float64 // изменение цены за 24 часа, %
+}
+
+// Funding — ставка фандинга бессрочного контракта
+type Funding struct {
+	Pair   Pair
+	Rate   float64   // доля за период: 0.0001 = 0.01%
+	NextAt time.Time // следующее списание
+}
+
+// OpenInterest — открытый интерес бессрочного контракта
+type OpenInterest struct {
+	Pair   Pair
+	Amount float64 // в базовой валюте
+	At     time.Time
+}
+
+// Instrument — торгуемый инструмент биржи
+type Instrument struct {
+	Category Category
+	Pair     Pair
+	Symbol   string    // символ в формате биржи: VANAUSDT, VANA-USDT-SWAP
+	Trading  bool      // торги открыты
+	ListedAt time.Time // начало торгов, если биржа его сообщает
+}
+
+// Провайдеры для market.provider в config.yaml
+const (
+	ProviderBybit   = "bybit"
+	ProviderBinance = "binance"
+	ProviderOKX     = "okx"
+)
+
+// Options — параметры HTTP-доступа клиента. Нулевое значение — боевые адреса
+// и http.DefaultTransport.
+type Options struct {
+	// BaseURL заменяет адреса API биржи (у Binance — и спота, и фьючерсов):
+	// прокси или стенд на httptest с записанными ответами (см. httpx.StandIn)
+	BaseURL   string
+	Transport http.RoundTripper // nil — http.DefaultTransport
+	// RPS — не больше стольких запросов в секунду; 0 — лимит провайдера по умолчанию
+	RPS float64
+}
+
+// New строит клиент провайдера.
+func New(provider string, opts Options) (Client, error) {
+	switch provider {
+	case ProviderBybit, "":
+		return newBybit(opts), nil
+	case ProviderBinance:
+		return newBinance(opts), nil
+	case ProviderOKX:
+		return newOKX(opts), nil
+	}
+	return nil, fmt.Errorf("unknown market provider %q (%s, %s, %s)", provider, ProviderBybit, ProviderBinance, ProviderOKX)
+}
+
+// base возвращает BaseURL без завершающего слэша либо def
+func (o Options) base(def string) string {
+	if o.BaseURL == "" {
+		return def
+	}
+	return strings.TrimRight(o.BaseURL, "/")
+}
+
+// klinePages делит [from, to) на отрезки не длиннее limit свечей по step
+// и вызывает fetch для каждого по порядку; свечи склеиваются и сортируются.
+func klinePages(q KlineQuery, limit int, fetch func(from, to time.Time) ([]Kline, error)) ([]Kline, error) {
+	step := q.Interval.Duration()
+	if step == 0 {
+		return nil, fmt.Errorf("interval %q: %w", q.Interval, ErrUnsupported)
+	}
+	var out []Kline
+	for start := q.From; start.Before(q.To); {
+		end := start.Add(time.Duration(limit) * step)
+		if q.To.Before(end) {
+			end = q.To
+		}
+		page, err := fetch(start, end)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		start = end
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OpenTime.Before(out[j].OpenTime) })
+	return out, nil
+}
+
+// limiter выдерживает паузу между запросами, чтобы не упираться в лимиты
+// публичного API (общий на все методы клиента)
+type limiter struct {
+	mu    sync.Mutex
+	every time.Duration
+	next  time.Time // раньше этого момента следующий запрос не уходит
+}
+
+func newLimiter(rps, def float64) *limiter {
+	if rps <= 0 {
+		rps = def
+	}
+	return &limiter{every: time.Duration(float64(time.Second) / rps)}
+}
+
+// wait ждёт своей очереди на запрос или отмены ctx
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.every)
+	l.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
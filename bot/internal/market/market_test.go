@@ -0,0 +1,237 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/httpx"
+)
+
+// Ответы бирж в testdata/<provider> — в формате их API, имена — по
+// httpx.FixtureName. Во всех трёх записан один и тот же рынок VANA/USDT,
+// поэтому после разбора клиенты должны вернуть одно и то же.
+
+var (
+	testPair = NewPair("vana", "usdt")
+	testFrom = time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	testTo   = testFrom.Add(3 * time.Hour)
+)
+
+// standIn поднимает httpx.StandIn для провайдера и возвращает клиент к нему.
+// Запрос без записи валит тест с именем недостающего файла.
+func standIn(t *testing.T, provider string) Client {
+	t.Helper()
+	dir := filepath.Join("testdata", provider)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		httpx.StandIn{Dir: dir}.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: no fixture %s for %s", provider, httpx.FixtureName(r), r.URL.RequestURI())
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	c, err := New(provider, Options{BaseURL: srv.URL, RPS: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b)) }
+
+func wantKlines() []Kline {
+	return []Kline{
+		{OpenTime: testFrom, Open: 7.10, High: 7.30, Low: 7.00, Close: 7.25, Volume: 1000, QuoteVolume: 7150},
+		{OpenTime: testFrom.Add(time.Hour), Open: 7.25, High: 7.60, Low: 7.20, Close: 7.50, Volume: 2000, QuoteVolume: 14800},
+		{OpenTime: testFrom.Add(2 * time.Hour), Open: 7.50, High: 7.55, Low: 7.35, Close: 7.40, Volume: 1500, QuoteVolume: 11200},
+	}
+}
+
+func TestClientsOnRecordedResponses(t *testing.T) {
+	listed := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	ms := func(v int64) time.Time { return time.UnixMilli(v).UTC() }
+	tests := []struct {
+		provider string
+		perp     Ticker // у OKX объём контрактов пересчитывается в котируемую валюту
+		spot     []Instrument
+		perps    []Instrument
+	}{
+		{
+			provider: ProviderBybit,
+			perp:     Ticker{Pair: testPair, Last: 7.35, High: 7.62, Low: 6.88, Volume: 800000, QuoteVolume: 5800000, ChangePct: 5},
+			spot: []Instrument{
+				{Category: Spot, Pair: testPair, Symbol: "VANAUSDT", Trading: true},
+				{Category: Spot, Pair: NewPair("OLD", "USDT"), Symbol: "OLDUSDT"},
+			},
+			// Две страницы по курсору; квартальный фьючерс отброшен
+			perps: []Instrument{
+				{Category: Perp, Pair: testPair, Symbol: "VANAUSDT", Trading: true, ListedAt: listed},
+				{Category: Perp, Pair: NewPair("BTC", "USDT"), Symbol: "BTCUSDT", Trading: true, ListedAt: ms(1585526400000)},
+			},
+		},
+		{
+			provider: ProviderBinance,
+			perp:     Ticker{Pair: testPair, Last: 7.35, High: 7.62, Low: 6.88, Volume: 800000, QuoteVolume: 5800000, ChangePct: 5},
+			spot: []Instrument{
+				{Category: Spot, Pair: testPair, Symbol: "VANAUSDT", Trading: true},
+				{Category: Spot, Pair: NewPair("OLD", "USDT"), Symbol: "OLDUSDT"},
+			},
+			perps: []Instrument{
+				{Category: Perp, Pair: testPair, Symbol: "VANAUSDT", Trading: true, ListedAt: listed},
+				{Category: Perp, Pair: NewPair("BTC", "USDT"), Symbol: "BTCUSDT", Trading: true, ListedAt: ms(1569398400000)},
+			},
+		},
+		{
+			provider: ProviderOKX,
+			perp:     Ticker{Pair: testPair, Last: 7.35, High: 7.62, Low: 6.88, Volume: 800000, QuoteVolume: 800000 * 7.35, ChangePct: 5},
+			spot: []Instrument{
+				{Category: Spot, Pair: testPair, Symbol: "VANA-USDT", Trading: true, ListedAt: listed},
+				{Category: Spot, Pair: NewPair("OLD", "USDT"), Symbol: "OLD-USDT", ListedAt: ms(1600000000000)},
+			},
+			// Инверсный контракт отброшен
+			perps: []Instrument{
+				{Category: Perp, Pair: testPair, Symbol: "VANA-USDT-SWAP", Trading: true, ListedAt: listed},
+				{Category: Perp, Pair: NewPair("BTC", "USDT"), Symbol: "BTC-USDT-SWAP", Trading: true, ListedAt: ms(1573557408000)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			c := standIn(t, tt.provider)
+			ctx := context.Background()
+
+			klines, err := c.Klines(ctx, KlineQuery{Category: Spot, Pair: testPair, Interval: Hour1, From: testFrom, To: testTo})
+			if err != nil {
+				t.Fatalf("Klines: %v", err)
+			}
+			want := wantKlines()
+			if len(klines) != len(want) {
+				t.Fatalf("Klines: got %d, want %d", len(klines), len(want))
+			}
+			for i, k := range klines {
+				w := want[i]
+				if !k.OpenTime.Equal(w.OpenTime) || !approx(k.Open, w.Open) || !approx(k.High, w.High) || !approx(k.Low, w.Low) ||
+					!approx(k.Close, w.Close) || !approx(k.Volume, w.Volume) || !approx(k.QuoteVolume, w.QuoteVolume) {
+					t.Errorf("kline %d = %+v, want %+v", i, k, w)
+				}
+			}
+
+			checkTicker := func(cat Category, want Ticker) {
+				got, err := c.Ticker(ctx, cat, testPair)
+				if err != nil {
+					t.Errorf("Ticker %s: %v", cat, err)
+					return
+				}
+				if got.Pair != want.Pair || !approx(got.Last, want.Last) || !approx(got.High, want.High) || !approx(got.Low, want.Low) ||
+					!approx(got.Volume, want.Volume) || !approx(got.QuoteVolume, want.QuoteVolume) || !approx(got.ChangePct, want.ChangePct) {
+					t.Errorf("Ticker %s = %+v, want %+v", cat, got, want)
+				}
+			}
+			checkTicker(Spot, Ticker{Pair: testPair, Last: 7.35, High: 7.60, Low: 6.90, Volume: 120000, QuoteVolume: 870000, ChangePct: 5})
+			checkTicker(Perp, tt.perp)
+
+			f, err := c.Funding(ctx, testPair)
+			if err != nil || !approx(f.Rate, 0.0001) || !f.NextAt.Equal(testTo.Add(4*time.Hour)) {
+				t.Errorf("Funding = %+v, %v", f, err)
+			}
+			oi, err := c.OpenInterest(ctx, testPair)
+			if err != nil || !approx(oi.Amount, 2_500_000) || !oi.At.Equal(testTo) {
+				t.Errorf("OpenInterest = %+v, %v", oi, err)
+			}
+
+			for cat, want := range map[Category][]Instrument{Spot: tt.spot, Perp: tt.perps} {
+				got, err := c.Instruments(ctx, cat)
+				if err != nil {
+					t.Errorf("Instruments %s: %v", cat, err)
+					continue
+				}
+				if len(got) != len(want) {
+					t.Errorf("Instruments %s = %+v, want %+v", cat, got, want)
+					continue
+				}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Errorf("Instruments %s[%d] = %+v, want %+v", cat, i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownSymbol(t *testing.T) {
+	missing := NewPair("NOPE", "USDT")
+	for _, provider := range []string{ProviderBybit, ProviderOKX} {
+		t.Run(provider, func(t *testing.T) {
+			// Bybit и OKX сообщают об ошибке в теле ответа со статусом 200
+			_, err := standIn(t, provider).Ticker(context.Background(), Spot, missing)
+			var apiErr *APIError
+			if !errors.Is(err, ErrUnknownSymbol) || !errors.As(err, &apiErr) || apiErr.Code == "" {
+				t.Errorf("err = %v, want APIError with ErrUnknownSymbol", err)
+			}
+		})
+	}
+}
+
+// errorServer отвечает на любой запрос статусом status с телом body
+func errorServer(t *testing.T, provider string, status int, header http.Header, body string) Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	c, err := New(provider, Options{BaseURL: srv.URL, RPS: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		status   int
+		header   http.Header
+		body     string
+		kind     error
+		code     string
+		wait     time.Duration
+	}{
+		{"binance bad symbol", ProviderBinance, http.StatusBadRequest, nil, `{"code":-1121,"msg":"Invalid symbol."}`, ErrUnknownSymbol, "-1121", 0},
+		{"binance weight limit", ProviderBinance, http.StatusBadRequest, nil, `{"code":-1003,"msg":"Too much request weight used."}`, ErrRateLimited, "-1003", 0},
+		{"binance ip ban", ProviderBinance, http.StatusTeapot, http.Header{"Retry-After": {"120"}}, "", ErrRateLimited, "", 2 * time.Minute},
+		{"bybit 429", ProviderBybit, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, "", ErrRateLimited, "", 30 * time.Second},
+		{"bybit 403", ProviderBybit, http.StatusForbidden, nil, "access too frequent", ErrRateLimited, "", 0},
+		{"bybit retCode", ProviderBybit, http.StatusOK, nil, `{"retCode":10006,"retMsg":"Too many visits!"}`, ErrRateLimited, "10006", 0},
+		{"okx rate limit", ProviderOKX, http.StatusTooManyRequests, nil, `{"code":"50011","msg":"Too Many Requests"}`, ErrRateLimited, "", 0},
+		{"okx server error", ProviderOKX, http.StatusBadGateway, nil, "<html>bad gateway</html>", nil, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := errorServer(t, tt.provider, tt.status, tt.header, tt.body).Ticker(context.Background(), Spot, testPair)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if tt.kind != nil && !errors.Is(err, tt.kind) || tt.kind == nil && errors.Unwrap(err) != nil {
+				t.Errorf("err = %v, want class %v", err, tt.kind)
+			}
+			if apiErr.Status != tt.status || apiErr.Code != tt.code || apiErr.RetryAfter != tt.wait {
+				t.Errorf("APIError = %+v, want status %d code %q retry %v", apiErr, tt.status, tt.code, tt.wait)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	okxBaseURL = "https://www.okx.com"
+	okxRPS     = 5 // у свечей лимит 20 запросов за 2 с, берём с запасом
+	okxKlines  = 100
+
+	okxCodeBadInstrument = "51001" // "Instrument ID does not exist"
+	okxCodeRateLimit     = "50011"
+)
+
+type okxClient struct {
+	api
+	base string
+}
+
+func newOKX(opts Options) *okxClient {
+	return &okxClient{api: newAPI(ProviderOKX, opts, okxRPS), base: opts.base(okxBaseURL)}
+}
+
+func (c *okxClient) Name() string { return ProviderOKX }
+
+type okxEnvelope struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// call запрашивает path и разбирает data в v
+func (c *okxClient) call(ctx context.Context, path string, v any) error {
+	body, status, err := c.get(ctx, c.base+path)
+	if err != nil {
+		return err
+	}
+	var env okxEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		if status != http.StatusOK {
+			return &APIError{Provider: ProviderOKX, Status: status}
+		}
+		return fmt.Errorf("decode okx response: %w", err)
+	}
+	if env.Code != "0" {
+		e := &APIError{Provider: ProviderOKX, Status: status, Code: env.Code, Message: env.Msg}
+		switch env.Code {
+		case okxCodeBadInstrument:
+			e.kind = ErrUnknownSymbol
+		case okxCodeRateLimit:
+			e.kind = ErrRateLimited
+		}
+		return e
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		return fmt.Errorf("decode okx data: %w", err)
+	}
+	return nil
+}
+
+// okxInstID — ID инструмента OKX: VANA-USDT (спот), VANA-USDT-SWAP (бессрочный)
+func okxInstID(cat Category, p Pair) (string, error) {
+	switch cat {
+	case Spot:
+		return p.Base + "-" + p.Quote, nil
+	case Perp:
+		return p.Base + "-" + p.Quote + "-SWAP", nil
+	}
+	return "", fmt.Errorf("okx category %q: %w", cat, ErrUnsupported)
+}
+
+func okxBar(i Interval) (string, error) {
+	switch i {
+	case Minute1, Minute5, Minute15:
+		return string(i), nil
+	case Hour1:
+		return "1H", nil
+	case Hour4:
+		return "4H", nil
+	case Day1:
+		return "1Dutc", nil // 1D у OKX — сутки по Гонконгу
+	}
+	return "", fmt.Errorf("okx interval %q: %w", i, ErrUnsupported)
+}
+
+func (c *okxClient) Klines(ctx context.Context, q KlineQuery) ([]Kline, error) {
+	inst, err := okxInstID(q.Category, q.Pair)
+	if err != nil {
+		return nil, err
+	}
+	bar, err := okxBar(q.Interval)
+	if err != nil {
+		return nil, err
+	}
+	return klinePages(q, okxKlines, func(from, to time.Time) ([]Kline, error) {
+		// [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm], новые — первыми;
+		// after — строго раньше ts, before — строго позже
+		var rows [][]string
+		path := fmt.Sprintf("/api/v5/market/history-candles?instId=%s&bar=%s&after=%d&before=%d&limit=%d",
+			inst, bar, to.UnixMilli(), from.UnixMilli()-1, okxKlines)
+		if err := c.call(ctx, path, &rows); err != nil {
+			return nil, err
+		}
+		out := make([]Kline, 0, len(rows))
+		for _, row := range rows {
+			if len(row) < 8 {
+				return nil, fmt.Errorf("okx kline: short row %v", row)
+			}
+			// У спота vol — в базовой валюте, у контрактов — в контрактах
+			vol := row[5]
+			if q.Category == Perp {
+				vol = row[6]
+			}
+			var p parser
+			out = append(out, Kline{
+				OpenTime: p.ms(row[0]),
+				Open:     p.num(row[1]), High: p.num(row[2]), Low: p.num(row[3]), Close: p.num(row[4]),
+				Volume: p.num(vol), QuoteVolume: p.num(row[7]),
+			})
+			if p.err != nil {
+				return nil, fmt.Errorf("okx kline: %w", p.err)
+			}
+		}
+		return out, nil
+	})
+}
+
+func (c *okxClient) Ticker(ctx context.Context, cat Category, p Pair) (Ticker, error) {
+	inst, err := okxInstID(cat, p)
+	if err != nil {
+		return Ticker{}, err
+	}
+	var data []struct {
+		Last      string `json:"last"`
+		Open24h   string `json:"open24h"`
+		High24h   string `json:"high24h"`
+		Low24h    string `json:"low24h"`
+		Vol24h    string `json:"vol24h"`    // спот — базовая валюта, контракты — контракты
+		VolCcy24h string `json:"volCcy24h"` // спот — котируемая валюта, контракты — базовая
+	}
+	if err := c.call(ctx, "/api/v5/market/ticker?instId="+inst, &data); err != nil {
+		return Ticker{}, err
+	}
+	if len(data) == 0 {
+		return Ticker{}, fmt.Errorf("okx %s: %w", inst, ErrUnknownSymbol)
+	}
+	t := data[0]
+	var ps parser
+	out := Ticker{Pair: p, Last: ps.num(t.Last), High: ps.num(t.High24h), Low: ps.num(t.Low24h)}
+	if cat == Spot {
+		out.Volume, out.QuoteVolume = ps.num(t.Vol24h), ps.num(t.VolCcy24h)
+	} else {
+		out.Volume = ps.num(t.VolCcy24h)
+		out.QuoteVolume = out.Volume * out.Last
+	}
+	if open := ps.num(t.Open24h); open > 0 {
+		out.ChangePct = (out.Last/open - 1) * 100
+	}
+	if ps.err != nil {
+		return Ticker{}, fmt.Errorf("okx ticker: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *okxClient) Funding(ctx context.Context, p Pair) (Funding, error) {
+	inst, _ := okxInstID(Perp, p)
+	var data []struct {
+		FundingRate string `json:"fundingRate"`
+		FundingTime string `json:"fundingTime"` // ближайшее списание
+	}
+	if err := c.call(ctx, "/api/v5/public/funding-rate?instId="+inst, &data); err != nil {
+		return Funding{}, err
+	}
+	if len(data) == 0 {
+		return Funding{}, fmt.Errorf("okx %s: %w", inst, ErrUnknownSymbol)
+	}
+	var ps parser
+	out := Funding{Pair: p, Rate: ps.num(data[0].FundingRate), NextAt: ps.ms(data[0].FundingTime)}
+	if ps.err != nil {
+		return Funding{}, fmt.Errorf("okx funding: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *okxClient) OpenInterest(ctx context.Context, p Pair) (OpenInterest, error) {
+	inst, _ := okxInstID(Perp, p)
+	var data []struct {
+		OICcy string `json:"oiCcy"` // в базовой валюте
+		TS    string `json:"ts"`
+	}
+	if err := c.call(ctx, "/api/v5/public/open-interest?instType=SWAP&instId="+inst, &data); err != nil {
+		return OpenInterest{}, err
+	}
+	if len(data) == 0 {
+		return OpenInterest{}, fmt.Errorf("okx %s: %w", inst, ErrUnknownSymbol)
+	}
+	var ps parser
+	out := OpenInterest{Pair: p, Amount: ps.num(data[0].OICcy), At: ps.ms(data[0].TS)}
+	if ps.err != nil {
+		return OpenInterest{}, fmt.Errorf("okx open interest: %w", ps.err)
+	}
+	return out, nil
+}
+
+func (c *okxClient) Instruments(ctx context.Context, cat Category) ([]Instrument, error) {
+	var instType string
+	switch cat {
+	case Spot:
+		instType = "SPOT"
+	case Perp:
+		instType = "SWAP"
+	default:
+		return nil, fmt.Errorf("okx category %q: %w", cat, ErrUnsupported)
+	}
+	var data []struct {
+		InstID   string `json:"instId"`
+		CtType   string `json:"ctType"` // у SWAP: linear (USDT) | inverse (монета)
+		State    string `json:"state"`
+		ListTime string `json:"listTime"`
+	}
+	if err := c.call(ctx, "/api/v5/public/instruments?instType="+instType, &data); err != nil {
+		return nil, err
+	}
+	out := make([]Instrument, 0, len(data))
+	for _, d := range data {
+		if cat == Perp && d.CtType != "linear" {
+			continue
+		}
+		parts := strings.Split(d.InstID, "-")
+		if len(parts) < 2 {
+			continue
+		}
+		var ps parser
+		out = append(out, Instrument{
+			Category: cat,
+			Pair:     NewPair(parts[0], parts[1]),
+			Symbol:   d.InstID,
+			Trading:  d.State == "live",
+			ListedAt: ps.ms(d.ListTime),
+		})
+		if ps.err != nil {
+			return nil, fmt.Errorf("okx instrument %s: %w", d.InstID, ps.err)
+		}
+	}
+	return out, nil
+}
@@ -10,21 +10,252 @@ const (
 	EventListing    EventType = "listing"
 	EventUnlock     EventType = "unlock"
 	EventAirdrop    EventType = "airdrop"
+
+	EventDelisting     EventType = "delisting"      // снятие с торгов
+	EventPremarket     EventType = "premarket"      // торги до листинга (Pre-Market)
+	EventHodlerAirdrop EventType = "hodler_airdrop" // Binance HODLer Airdrops — раздача держателям BNB
+	EventMegadrop      EventType = "megadrop"       // Binance Megadrop — лок BNB + Web3-задания
+)
+
+// Valid сообщает, известен ли тип
+func (t EventType) Valid() bool {
+	switch t {
+	case EventLaunchpool, EventListing, EventUnlock, EventAirdrop,
+		EventDelisting, EventPremarket, EventHodlerAirdrop, EventMegadrop:
+		return true
+	}
+	return false
+}
+
+// EventSubtype уточняет тип события: для листингов и делистингов — рынок
+type EventSubtype string
+
+const (
+	SubtypeSpotListing   EventSubtype = "spot_listing"   // спот
+	SubtypeFuturesLaunch EventSubtype = "futures_launch" // бессрочные фьючерсы
+)
+
+// Valid сообщает, известен ли подтип; пустой подтип допустим
+func (s EventSubtype) Valid() bool {
+	switch s {
+	case "", SubtypeSpotListing, SubtypeFuturesLaunch:
+		return true
+	}
+	return false
+}
+
+// DateConfidence — насколько точно известно время события
+type DateConfidence string
+
+const (
+	DateExact    DateConfidence = "exact"     // известны дата и время
+	DateOnly     DateConfidence = "date_only" // известна только дата, время 00:00 UTC условно
+	DateInferred DateConfidence = "inferred"  // даты нет, взята дата публикации анонса
 )
 
+// UnknownToken — тикер события, когда источник его не назвал
+const UnknownToken = "UNKNOWN"
+
 // Event — одно крипто-событие
 type Event struct {
-	ID       string    `json:"id"`       // уникальный идентификатор (source:token:date)
-	Type     EventType `json:"type"`     // launchpool | listing | unlock | airdrop
-	Source   string    `json:"source"`   // binance | bybit | okx | tokenunlocks | airdrops
-	Token    string    `json:"token"`    // тикер токена, напр. VANA
-	Title    string    `json:"title"`    // полное название события
-	Date     time.Time `json:"date"`     // дата/время события (UTC)
-	URL      string    `json:"url"`      // ссылка на анонс
-	Details  string    `json:"details"`  // доп. данные (пары, % разлока и т.д.)
+	ID      string    `json:"id"`      // уникальный идентификатор (source:article:TOKEN; для разлоков source:TOKEN:date)
+	Type    EventType `json:"type"`    // launchpool | listing | unlock | airdrop | delisting | premarket | hodler_airdrop | megadrop
+	Source  string    `json:"source"`  // binance | bybit | okx | tokenunlocks | airdrops
+	Token   string    `json:"token"`   // тикер токена, напр. VANA
+	Title   string    `json:"title"`   // полное название события
+	EventAt time.Time `json:"date"`    // дата/время события (UTC)
+	URL     string    `json:"url"`     // ссылка на анонс
+	Details string    `json:"details"` // доп. данные (пары, % разлока и т.д.)
+
+	Subtype EventSubtype `json:"subtype,omitempty"` // spot_listing | futures_launch; пусто — не уточнён
+
+	// Venues — площадки события после слияния дублей из разных источников
+	// (включая основную), по времени начала. Заполняется только в сводном
+	// представлении, в хранилище каждая площадка — отдельное событие.
+	Venues []Venue `json:"venues,omitempty"`
+
+	GroupID string `json:"group_id,omitempty"` // общий для событий одного анонса (несколько токенов)
+	Rule    string `json:"rule,omitempty"`     // правило классификатора, определившее тип (для анонсов бирж)
+
+	AnnouncedAt    time.Time      `json:"announced_at,omitzero"`     // дата публикации анонса (UTC)
+	DateConfidence DateConfidence `json:"date_confidence,omitempty"` // точность EventAt
+
+	// Changes — история изменений события между обновлениями (старые — первыми)
+	Changes []Change `json:"changes,omitempty"`
+	// MissedScans — сколько успешных опросов подряд источник не возвращал событие
+	MissedScans int `json:"missed_scans,omitempty"`
+
+	// Tokenomics — предложение и оценка токена на момент последнего обновления
+	Tokenomics *Tokenomics `json:"tokenomics,omitempty"`
+	// Unlock — размер разлока; только для событий unlock, если источник его знает
+	Unlock *Unlock `json:"unlock,omitempty"`
+	// Outcome — реакция цены после события; заполняется для событий архива
+	Outcome *Outcome `json:"outcome,omitempty"`
 
 	// Флаги отправки — чтобы не дублировать уведомления
 	SentDigest bool `json:"sent_digest"`
 	Sent24h    bool `json:"sent_24h"`
 	Sent2h     bool `json:"sent_2h"`
 }
+
+// Venue — одна площадка сводного события: своё время и свой анонс
+type Venue struct {
+	ID             string         `json:"id"` // ID исходного события
+	Source         string         `json:"source"`
+	Subtype        EventSubtype   `json:"subtype,omitempty"`
+	EventAt        time.Time      `json:"date"`
+	AnnouncedAt    time.Time      `json:"announced_at,omitzero"`
+	DateConfidence DateConfidence `json:"date_confidence,omitempty"`
+	URL            string         `json:"url"`
+}
+
+// IDs возвращает ID события и всех его площадок без повторов
+func (e Event) IDs() []string {
+	ids := []string{e.ID}
+	for _, v := range e.Venues {
+		if v.ID != e.ID {
+			ids = append(ids, v.ID)
+		}
+	}
+	return ids
+}
+
+// Поля события в журнале изменений
+const (
+	FieldDate    = "date"
+	FieldTitle   = "title"
+	FieldDetails = "details"
+	FieldStatus  = "status" // active | missing — пропало ли событие из источника
+)
+
+// Значения поля status
+const (
+	StatusActive  = "active"
+	StatusMissing = "missing"
+)
+
+// Change — одно изменение поля события, замеченное при обновлении
+type Change struct {
+	Field  string    `json:"field"`
+	Old    string    `json:"old"`
+	New    string    `json:"new"`
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// Confidence возвращает точность даты события. Для записей старого кэша без
+// date_confidence выводит её из времени: ненулевое время — exact, иначе date_only.
+func (e Event) Confidence() DateConfidence {
+	if e.DateConfidence != "" {
+		return e.DateConfidence
+	}
+	if e.EventAt.Hour() != 0 || e.EventAt.Minute() != 0 {
+		return DateExact
+	}
+	return DateOnly
+}
+
+// Horizon — через сколько после начала торгов смотреть цену
+type Horizon struct {
+	Name  string // ключ в Outcome.Prices: 1h, 4h, 24h, 7d
+	After time.Duration
+}
+
+// OutcomeHorizons — горизонты реакции цены, по возрастанию; последний —
+// окно, за которое считаются максимальный рост и просадка
+var OutcomeHorizons = []Horizon{
+	{"1h", time.Hour},
+	{"4h", 4 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+}
+
+// Outcome — что делала цена после события, по часовым свечам
+type Outcome struct {
+	Provider string    `json:"provider"` // откуда свечи: bybit | binance | okx
+	Symbol   string    `json:"symbol"`   // пара, напр. VANA/USDT
+	StartAt  time.Time `json:"start_at"` // первая свеча не раньше события — от неё считаются горизонты
+	PriceAt  float64   `json:"price_at"` // цена открытия первой свечи
+
+	Prices      map[string]float64 `json:"prices,omitempty"` // горизонт → цена закрытия
+	MaxDrawup   float64            `json:"max_drawup"`       // макс. рост от PriceAt за окно, %
+	MaxDrawdown float64            `json:"max_drawdown"`     // макс. падение от PriceAt за окно, % (≤ 0)
+
+	NoMarket  bool      `json:"no_market,omitempty"` // символом не торгуют — цены не будет
+	Complete  bool      `json:"complete,omitempty"`  // окно прошло, больше не обновляется
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Return — изменение цены к горизонту h в процентах; false — цены ещё нет
+func (o Outcome) Return(h string) (float64, bool) {
+	p, ok := o.Prices[h]
+	if !ok || o.PriceAt == 0 {
+		return 0, false
+	}
+	return (p/o.PriceAt - 1) * 100, true
+}
+
+// Tokenomics — предложение и оценка токена. Нулевое поле — провайдер его не знает.
+type Tokenomics struct {
+	Source string `json:"source"` // провайдер: coingecko | static
+
+	Circulating float64 `json:"circulating,omitempty"` // в обращении
+	Total       float64 `json:"total,omitempty"`       // выпущено
+	Max         float64 `json:"max,omitempty"`         // максимум; 0 — не ограничен или неизвестен
+
+	Price      float64 `json:"price,omitempty"`       // USD
+	MarketCap  float64 `json:"market_cap,omitempty"`  // USD, по циркулирующему предложению
+	FDV        float64 `json:"fdv,omitempty"`         // USD, по максимальному (или выпущенному) предложению
+	FloatRatio float64 `json:"float_ratio,omitempty"` // доля в обращении, 0..1
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Unlock — размер разлока токенов. Нулевое поле — источник его не знает.
+type Unlock struct {
+	Percent  float64 `json:"percent,omitempty"`   // % общего предложения
+	ValueUSD float64 `json:"value_usd,omitempty"` // оценка в USD на момент опроса
+	// Allocations — из чего складывается разлок этого дня: кому и как
+	// выходят токены. Пусто — источник не разбивает разлок.
+	Allocations []UnlockAllocation `json:"allocations,omitempty"`
+
+	// Уровень разлока по unlocks.tiers в config.yaml: название, значок и
+	// номер (0 — самый серьёзный). Не хранится — календарь назначает его при
+	// выдаче событий, так что правка порогов действует сразу.
+	Severity string `json:"-"`
+	Icon     string `json:"-"`
+	Tier     int    `json:"-"`
+}
+
+// UnlockCategory — кому достаются токены разлока
+type UnlockCategory string
+
+const (
+	UnlockTeam      UnlockCategory = "team"      // команда и советники
+	UnlockInvestors UnlockCategory = "investors" // фонды и раунды продаж
+	UnlockEcosystem UnlockCategory = "ecosystem" // экосистема, фонд проекта, сообщество
+	UnlockOther     UnlockCategory = "other"
+)
+
+// VestingKind — как токены разлока выходят на рынок
+type VestingKind string
+
+const (
+	VestingCliff  VestingKind = "cliff"  // разом в дату разлока
+	VestingLinear VestingKind = "linear" // равными частями; в событии — доля этого дня
+)
+
+// UnlockAllocation — часть разлока одной категории держателей
+type UnlockAllocation struct {
+	Category UnlockCategory `json:"category,omitempty"`
+	Kind     VestingKind    `json:"kind,omitempty"` // пусто — источник не указал
+	Percent  float64        `json:"percent,omitempty"`
+	ValueUSD float64        `json:"value_usd,omitempty"`
+}
+
+// FDVRatio — FDV/MCap; 0 — не известно
+func (t Tokenomics) FDVRatio() float64 {
+	if t.MarketCap == 0 {
+		return 0
+	}
+	return t.FDV / t.MarketCap
+}
@@ -9,6 +9,7 @@ import (
 
 	"crypto-bot/internal/calendar"
 	"crypto-bot/internal/model"
+	"crypto-bot/internal/store"
 )
 
 // CommandHandler routes Telegram commands to the appropriate handlers.
@@ -25,7 +26,11 @@ func NewCommandHandler(tg *Telegram, agg *calendar.Aggregator) *CommandHandler {
 // Handle parses the command and dispatches to the right handler.
 func (h *CommandHandler) Handle(chatID int64, text string) {
 	// Strip @BotName suffix (sent in group chats: /cmd@BotName)
-	cmd := strings.ToLower(strings.SplitN(text, " ", 2)[0])
+	args := strings.Fields(text)
+	if len(args) == 0 {
+		return
+	}
+	cmd := strings.ToLower(args[0])
 	if at := strings.Index(cmd, "@"); at != -1 {
 		cmd = cmd[:at]
 	}
@@ -42,15 +47,29 @@ func (h *CommandHandler) Handle(chatID int64, text string) {
 	case "/week":
 		h.handleWeek(chatID)
 	case "/listings":
-		h.handleByType(chatID, model.EventListing, "Предстоящие листинги")
+		h.handleByType(chatID, "Предстоящие листинги", model.EventListing)
+	case "/delistings":
+		h.handleByType(chatID, "Предстоящие делистинги", model.EventDelisting)
+	case "/premarket":
+		h.handleByType(chatID, "Pre-Market", model.EventPremarket)
 	case "/unlocks":
-		h.handleByType(chatID, model.EventUnlock, "Предстоящие разлоки")
+		h.handleByType(chatID, "Предстоящие разлоки", model.EventUnlock)
 	case "/airdrops":
-		h.handleByType(chatID, model.EventAirdrop, "Предстоящие аирдропы / TGE")
+		h.handleByType(chatID, "Предстоящие аирдропы / TGE", model.EventAirdrop, model.EventHodlerAirdrop)
 	case "/launchpools":
-		h.handleByType(chatID, model.EventLaunchpool, "Предстоящие лаунчпулы")
+		h.handleByType(chatID, "Предстоящие лаунчпулы", model.EventLaunchpool)
+	case "/megadrops":
+		h.handleByType(chatID, "Megadrop", model.EventMegadrop)
+	case "/history":
+		h.handleHistory(chatID, args[1:])
+	case "/unlock":
+		h.handleUnlock(chatID, args[1:])
+	case "/strategies":
+		h.handleStrategies(chatID)
 	case "/refresh":
 		h.handleRefresh(chatID)
+	case "/status":
+		h.handleStatus(chatID)
 	}
 }
 
@@ -65,11 +84,10 @@ func (h *CommandHandler) handleStart(chatID int64) {
 }
 
 func (h *CommandHandler) handleDigest(chatID int64) {
-	events := calendar.EventsForWeek(h.agg.Events())
-	now := time.Now().UTC()
-	weekEnd := now.Add(7 * 24 * time.Hour)
+	events := calendar.EventsForWeek(h.agg.Events(), h.agg.Windows())
+	weekStart, weekEnd := calendar.DigestPeriod(h.agg.Windows())
 	// Reuse FormatDigest for the full week digest view
-	msg := FormatDigest(events, now, weekEnd)
+	msg := FormatDigest(events, weekStart, weekEnd)
 	h.send(chatID, msg)
 }
 
@@ -84,24 +102,53 @@ func (h *CommandHandler) handleTomorrow(chatID int64) {
 }
 
 func (h *CommandHandler) handleWeek(chatID int64) {
-	events := calendar.EventsForWeek(h.agg.Events())
+	events := calendar.EventsForWeek(h.agg.Events(), h.agg.Windows())
 	h.send(chatID, FormatEventList(events, "События на неделю"))
 }
 
-func (h *CommandHandler) handleByType(chatID int64, evType model.EventType, header string) {
-	events := calendar.EventsUpcoming(h.agg.Events(), evType)
+func (h *CommandHandler) handleByType(chatID int64, header string, types ...model.EventType) {
+	events := calendar.EventsUpcoming(h.agg.Events(), h.agg.Windows(), types...)
 	h.send(chatID, FormatEventList(events, header))
 }
 
+func (h *CommandHandler) handleHistory(chatID int64, args []string) {
+	if len(args) == 0 {
+		h.send(chatID, escMD2("Укажите тикер: /history TOKEN"))
+		return
+	}
+	token := strings.ToUpper(args[0])
+	events := h.agg.History(store.Query{Token: token})
+	h.send(chatID, FormatHistory(token, events))
+}
+
+func (h *CommandHandler) handleUnlock(chatID int64, args []string) {
+	if len(args) == 0 {
+		h.send(chatID, escMD2("Укажите тикер: /unlock TOKEN"))
+		return
+	}
+	token := strings.ToUpper(args[0])
+	steps, horizon := h.agg.UnlockSchedule(token)
+	h.send(chatID, FormatUnlockSchedule(token, steps, horizon))
+}
+
+func (h *CommandHandler) handleStrategies(chatID int64) {
+	h.send(chatID, FormatStrategies(h.agg.StrategyList(), h.agg.StrategiesFile()))
+}
+
 func (h *CommandHandler) handleRefresh(chatID int64) {
 	if err := h.tg.SendToChat(chatID, "🔄 Обновляю\\.\\.\\."); err != nil {
 		log.Printf("[commands] refresh ack send failed: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	events := h.agg.Refresh(ctx)
+	// Предел опроса каждого источника задаёт сам агрегатор (scanner.scan_timeout_seconds),
+	// как и при плановом обновлении
+	events := h.agg.Refresh(context.Background())
 	msg := fmt.Sprintf("✅ Обновлено: найдено *%d* событий", len(events))
 	h.send(chatID, msg)
+	// Токеномика новых событий — в фоне, как после планового обновления
+	go h.agg.EnrichTokenomics(context.Background())
+}
+
+func (h *CommandHandler) handleStatus(chatID int64) {
+	h.send(chatID, FormatStatus(h.agg.Status(), time.Now().UTC()))
 }
@@ -1,11 +1,16 @@
 package notify
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"crypto-bot/internal/calendar"
 	"crypto-bot/internal/model"
+	"crypto-bot/internal/strategy"
 )
 
 // escMD2 экранирует специальные символы для Telegram MarkdownV2
@@ -33,39 +38,15 @@ func FormatDigest(events []model.Event, weekStart, weekEnd time.Time) string {
 	sb.WriteString(fmt.Sprintf("📅 *СОБЫТИЯ НЕДЕЛИ*\n_%s — %s_\n",
 		escMD2(startStr), escMD2(endStr)))
 
-	launchpools := filterByType(events, model.EventLaunchpool)
-	listings := filterByType(events, model.EventListing)
-	unlocks := filterByType(events, model.EventUnlock)
-	airdrops := filterByType(events, model.EventAirdrop)
-
-	if len(launchpools) > 0 {
-		sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
-		sb.WriteString(fmt.Sprintf("🌾 *LAUNCHPOOL* \\(%d\\)\n\n", len(launchpools)))
-		for _, e := range launchpools {
-			writeDigestEvent(&sb, e)
-		}
-	}
-
-	if len(listings) > 0 {
-		sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
-		sb.WriteString(fmt.Sprintf("🆕 *ЛИСТИНГИ* \\(%d\\)\n\n", len(listings)))
-		for _, e := range listings {
-			writeDigestEvent(&sb, e)
-		}
-	}
-
-	if len(unlocks) > 0 {
-		sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
-		sb.WriteString(fmt.Sprintf("🔓 *РАЗЛОКИ* \\(%d\\)\n\n", len(unlocks)))
-		for _, e := range unlocks {
-			writeDigestEvent(&sb, e)
+	for _, t := range typeOrder {
+		group := bySeverity(filterByType(events, t))
+		if len(group) == 0 {
+			continue
 		}
-	}
-
-	if len(airdrops) > 0 {
 		sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
-		sb.WriteString(fmt.Sprintf("🪂 *TGE / AIRDROP* \\(%d\\)\n\n", len(airdrops)))
-		for _, e := range airdrops {
+		sb.WriteString(fmt.Sprintf("%s *%s* \\(%d\\)\n\n",
+			eventIcon(t), escMD2(strings.ToUpper(typeLabelRu(t))), len(group)))
+		for _, e := range group {
 			writeDigestEvent(&sb, e)
 		}
 	}
@@ -85,74 +66,208 @@ func FormatDigest(events []model.Event, weekStart, weekEnd time.Time) string {
 // writeDigestEvent пишет одно событие в дайджесте
 func writeDigestEvent(sb *strings.Builder, e model.Event) {
 	sb.WriteString(fmt.Sprintf("▸ *%s* — %s\n",
-		escMD2(e.Token), escMD2(capitalize(e.Source))))
-	sb.WriteString(fmt.Sprintf("  📅 %s", escMD2(fmtDate(e.Date))))
-	if !e.Date.IsZero() && (e.Date.Hour() != 0 || e.Date.Minute() != 0) {
-		sb.WriteString(fmt.Sprintf(", %s UTC", escMD2(e.Date.UTC().Format("15:04"))))
-	}
-	sb.WriteString("\n")
-	if e.Details != "" {
-		sb.WriteString(fmt.Sprintf("  ℹ️ %s\n", escMD2(e.Details)))
-	}
-	if e.URL != "" {
+		escMD2(e.Token), escMD2(fmtVenue(e))))
+	sb.WriteString(fmt.Sprintf("  📅 %s\n", escMD2(fmtWhen(e))))
+	writeVenues(sb, e, "  ")
+	writeDetails(sb, e, "  ")
+	if e.URL != "" && len(e.Venues) < 2 {
 		sb.WriteString(fmt.Sprintf("  🔗 [Подробнее](%s)\n", e.URL))
 	}
 }
 
-// FormatAlert24h формирует алерт за 24 часа до события
-func FormatAlert24h(e model.Event) string {
-	icon, label, strategy := eventMeta(e)
+// FormatAlert24h формирует алерт за 24 часа до события; matches — стратегии,
+// которые событию подходят (блок стратегии выводится, только если есть)
+func FormatAlert24h(e model.Event, matches []strategy.Match) string {
+	icon, label := eventMeta(e)
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("%s *ЗАВТРА \\| %s*\n", icon, escMD2(label)))
 	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
 	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("*%s* — %s\n", escMD2(e.Token), escMD2(e.Title)))
-	sb.WriteString(fmt.Sprintf("📅 %s UTC\n", escMD2(e.Date.UTC().Format("02 Jan 2006, 15:04"))))
-	sb.WriteString(fmt.Sprintf("📍 %s\n", escMD2(capitalize(e.Source))))
-	sb.WriteString("\n")
-	if strategy != "" {
-		sb.WriteString(fmt.Sprintf("💡 *Стратегия:* %s\n", escMD2(strategy)))
+	sb.WriteString(fmt.Sprintf("📅 %s\n", escMD2(fmtWhen(e))))
+	sb.WriteString(fmt.Sprintf("📍 %s\n", escMD2(fmtVenue(e))))
+	writeVenues(&sb, e, "")
+	writeDetails(&sb, e, "")
+	if e.Tokenomics != nil {
+		sb.WriteString(fmt.Sprintf("💰 %s\n", escMD2(fmtTokenomics(*e.Tokenomics))))
 	}
-	if e.URL != "" {
+	sb.WriteString("\n")
+	writeStrategies(&sb, matches)
+	if e.URL != "" && len(e.Venues) < 2 {
 		sb.WriteString(fmt.Sprintf("🔗 [Анонс](%s)\n", e.URL))
 	}
 	return sb.String()
 }
 
 // FormatAlert2h формирует алерт за 2 часа до события
-func FormatAlert2h(e model.Event) string {
-	_, label, strategy := eventMeta(e)
+func FormatAlert2h(e model.Event, matches []strategy.Match) string {
+	_, label := eventMeta(e)
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("🚨 *ЧЕРЕЗ 2 ЧАСА \\| %s*\n", escMD2(label)))
 	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
 	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("*%s* запускается в *%s UTC*\n",
-		escMD2(e.Token), escMD2(e.Date.UTC().Format("15:04"))))
-	sb.WriteString(fmt.Sprintf("📍 %s\n", escMD2(capitalize(e.Source))))
-	sb.WriteString("\n")
-	if strategy != "" {
-		sb.WriteString(fmt.Sprintf("💡 *Стратегия:* %s\n", escMD2(strategy)))
+		escMD2(e.Token), escMD2(e.EventAt.UTC().Format("15:04"))))
+	sb.WriteString(fmt.Sprintf("📍 %s\n", escMD2(fmtVenue(e))))
+	writeVenues(&sb, e, "")
+	if e.Tokenomics != nil {
+		sb.WriteString(fmt.Sprintf("💰 %s\n", escMD2(fmtTokenomics(*e.Tokenomics))))
 	}
-	if e.URL != "" {
+	sb.WriteString("\n")
+	writeStrategies(&sb, matches)
+	if e.URL != "" && len(e.Venues) < 2 {
 		sb.WriteString(fmt.Sprintf("🔗 [Анонс](%s)\n", e.URL))
 	}
 	return sb.String()
 }
 
-func eventMeta(e model.Event) (icon, label, strategy string) {
+func eventMeta(e model.Event) (icon, label string) {
 	switch e.Type {
 	case model.EventLaunchpool:
-		return "🌾", "LAUNCHPOOL", "Launchpool Harvest (3x, шорт за 12-24ч до листинга)"
+		return "🌾", "LAUNCHPOOL"
+	case model.EventMegadrop:
+		return "🎯", "MEGADROP"
 	case model.EventListing:
-		return "🆕", "ЛИСТИНГ", "Token Splash Short (4x, ждать RSI>85 + Volume>300%)"
+		if e.Subtype == model.SubtypeFuturesLaunch {
+			return "📈", "ФЬЮЧЕРСЫ"
+		}
+		return "🆕", "ЛИСТИНГ"
+	case model.EventPremarket:
+		return "⏳", "PRE-MARKET"
+	case model.EventDelisting:
+		return "📉", "ДЕЛИСТИНГ"
 	case model.EventUnlock:
-		return "🔓", "РАЗЛОК", "VC-Gravity (шорт при разлоке >5% supply)"
+		return "🔓", "РАЗЛОК"
 	case model.EventAirdrop:
-		return "🪂", "AIRDROP/TGE", "TGE Short (4x, продать на спайке первых минут)"
+		return "🪂", "AIRDROP/TGE"
+	case model.EventHodlerAirdrop:
+		return "🎁", "HODLER AIRDROP"
+	}
+	return "📌", string(e.Type)
+}
+
+// writeStrategies пишет подходящие стратегии с разбором фильтров и
+// чек-листом того, что надо проверить вручную
+func writeStrategies(sb *strings.Builder, matches []strategy.Match) {
+	for _, m := range matches {
+		sb.WriteString(fmt.Sprintf("💡 *%s* \\(%d/%d\\)", escMD2(m.Strategy.Name), m.Passed, len(m.Results)))
+		if m.Strategy.Hint != "" {
+			sb.WriteString(" — " + escMD2(m.Strategy.Hint))
+		}
+		sb.WriteString("\n")
+		if pos := fmtPosition(m.Strategy.Position); pos != "" {
+			sb.WriteString("  " + escMD2(pos) + "\n")
+		}
+		for _, r := range m.Results {
+			mark := "❌"
+			switch {
+			case r.Pass:
+				mark = "✅"
+			case !r.Known:
+				mark = "❔"
+			}
+			line := fmtFilter(r.Filter)
+			if r.Known {
+				line += ": " + fmtMetric(r.Filter.Metric, r.Value)
+			} else {
+				line += ": нет данных"
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s\n", mark, escMD2(line)))
+		}
+		for _, item := range m.Strategy.Manual {
+			sb.WriteString(fmt.Sprintf("  ☐ %s\n", escMD2(item)))
+		}
+	}
+}
+
+// fmtPosition — параметры сделки одной строкой:
+// "шорт 3x · SL +12% · TP −25% / −45% · тайм-стоп 48ч"; пусто — не заданы
+func fmtPosition(p strategy.Position) string {
+	var parts []string
+	side, loss, profit := "шорт", "+", "−"
+	if p.Side == strategy.SideLong {
+		side, loss, profit = "лонг", "−", "+"
+	}
+	if p.Leverage > 0 {
+		side += fmt.Sprintf(" %gx", p.Leverage)
+	}
+	if p.StopLossPct > 0 {
+		parts = append(parts, fmt.Sprintf("SL %s%g%%", loss, p.StopLossPct))
+	}
+	if len(p.TakeProfitPct) > 0 {
+		tps := make([]string, len(p.TakeProfitPct))
+		for i, tp := range p.TakeProfitPct {
+			tps[i] = fmt.Sprintf("%s%g%%", profit, tp)
+		}
+		parts = append(parts, "TP "+strings.Join(tps, " / "))
+	}
+	if p.TimeStop > 0 {
+		parts = append(parts, "тайм-стоп "+fmtHorizon(p.TimeStop))
+	}
+	if p.Leverage == 0 && len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{side}, parts...), " · ")
+}
+
+// fmtHorizon — срок в часах, а от трёх суток — в днях: "48ч", "7 дн"
+func fmtHorizon(d time.Duration) string {
+	if d >= 72*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d дн", d/(24*time.Hour))
+	}
+	return fmt.Sprintf("%gч", d.Hours())
+}
+
+// fmtFilter — условие фильтра для людей: "FDV > $300M", "Float < 25%"
+func fmtFilter(f strategy.Filter) string {
+	return fmt.Sprintf("%s %s %s", f.Metric.Info().Label, f.Op, fmtMetric(f.Metric, f.Value))
+}
+
+// fmtMetric — значение метрики в её единицах
+func fmtMetric(m strategy.Metric, v float64) string {
+	switch m.Info().Unit {
+	case strategy.UnitUSD:
+		return fmtUSD(v)
+	case strategy.UnitShare:
+		return fmt.Sprintf("%.0f%%", v*100)
+	case strategy.UnitRatio:
+		return fmt.Sprintf("×%.1f", v)
+	case strategy.UnitDays:
+		return fmt.Sprintf("%.0f дн", v)
+	case strategy.UnitFlag:
+		if v != 0 {
+			return "да"
+		}
+		return "нет"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// typeOrder — порядок секций в дайджесте и списках событий
+var typeOrder = []model.EventType{
+	model.EventLaunchpool, model.EventMegadrop, model.EventHodlerAirdrop,
+	model.EventPremarket, model.EventListing, model.EventDelisting,
+	model.EventUnlock, model.EventAirdrop,
+}
+
+// fmtVenue — биржа и рынок события: "Binance", "Binance · фьючерсы";
+// для сводного события — все биржи: "Binance, Bybit, OKX"
+func fmtVenue(e model.Event) string {
+	if len(e.Venues) > 1 {
+		names := make([]string, 0, len(e.Venues))
+		for _, v := range e.Venues {
+			if name := capitalize(v.Source); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
 	}
-	return "📌", string(e.Type), ""
+	if label := subtypeLabelRu(e.Subtype); label != "" {
+		return capitalize(e.Source) + " · " + label
+	}
+	return capitalize(e.Source)
 }
 
 func filterByType(events []model.Event, t model.EventType) []model.Event {
@@ -165,6 +280,50 @@ func filterByType(events []model.Event, t model.EventType) []model.Event {
 	return out
 }
 
+// fmtWhen форматирует дату события с учётом её точности:
+// "21 фев, 10:00 UTC", "21 фев, время неизвестно", "дата TBD (анонс 18 фев)"
+func fmtWhen(e model.Event) string {
+	return fmtWhenAt(e.EventAt, e.AnnouncedAt, e.Confidence())
+}
+
+func fmtWhenAt(at, announced time.Time, confidence model.DateConfidence) string {
+	switch confidence {
+	case model.DateExact:
+		return fmt.Sprintf("%s, %s UTC", fmtDate(at), at.UTC().Format("15:04"))
+	case model.DateInferred:
+		if announced.IsZero() {
+			announced = at
+		}
+		return fmt.Sprintf("дата TBD (анонс %s)", fmtDate(announced))
+	}
+	return fmt.Sprintf("%s, время неизвестно", fmtDate(at))
+}
+
+// writeVenues перечисляет площадки сводного события — каждая со своим
+// временем и анонсом; первой идёт та, где торги начнутся раньше.
+// Для события с одной площадкой ничего не пишет.
+func writeVenues(sb *strings.Builder, e model.Event, indent string) {
+	if len(e.Venues) < 2 {
+		return
+	}
+	for i, v := range e.Venues {
+		mark := "▫️"
+		if i == 0 {
+			mark = "🥇"
+		}
+		venue := capitalize(v.Source)
+		if label := subtypeLabelRu(v.Subtype); label != "" {
+			venue += " · " + label
+		}
+		sb.WriteString(fmt.Sprintf("%s%s %s — %s", indent, mark, escMD2(venue),
+			escMD2(fmtWhenAt(v.EventAt, v.AnnouncedAt, v.DateConfidence))))
+		if v.URL != "" {
+			sb.WriteString(fmt.Sprintf(" [анонс](%s)", v.URL))
+		}
+		sb.WriteString("\n")
+	}
+}
+
 func fmtDate(t time.Time) string {
 	months := []string{"", "янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"}
 	return fmt.Sprintf("%d %s", t.Day(), months[t.Month()])
@@ -183,14 +342,472 @@ func FormatHelp() string {
 	sb.WriteString(escMD2("/digest   — дайджест недели") + "\n")
 
 	sb.WriteString("\n🔎 *По категориям:*\n")
-	sb.WriteString(escMD2("/listings    — предстоящие листинги") + "\n")
+	sb.WriteString(escMD2("/listings    — предстоящие листинги (спот и фьючерсы)") + "\n")
+	sb.WriteString(escMD2("/premarket   — торги Pre-Market") + "\n")
+	sb.WriteString(escMD2("/delistings  — делистинги") + "\n")
 	sb.WriteString(escMD2("/unlocks     — предстоящие разлоки") + "\n")
-	sb.WriteString(escMD2("/airdrops    — аирдропы и TGE") + "\n")
+	sb.WriteString(escMD2("/airdrops    — аирдропы, TGE и HODLer Airdrops") + "\n")
 	sb.WriteString(escMD2("/launchpools — лаунчпулы") + "\n")
+	sb.WriteString(escMD2("/megadrops   — Megadrop") + "\n")
+	sb.WriteString(escMD2("/history TOKEN — прошедшие события тикера") + "\n")
+	sb.WriteString(escMD2("/unlock TOKEN  — график разлоков тикера") + "\n")
+	sb.WriteString(escMD2("/strategies  — действующие стратегии") + "\n")
 
 	sb.WriteString("\n⚙️ *Управление:*\n")
 	sb.WriteString(escMD2("/refresh — обновить данные") + "\n")
+	sb.WriteString(escMD2("/status  — состояние источников") + "\n")
+
+	return sb.String()
+}
+
+// FormatStatus renders the health of every source for the /status command.
+// Counters are fetched / parsed / kept from the last successful scan.
+func FormatStatus(statuses []calendar.SourceStatus, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("🩺 *Состояние источников*\n")
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+
+	if len(statuses) == 0 {
+		sb.WriteString("\n" + escMD2("Источники не включены.") + "\n")
+		return sb.String()
+	}
+
+	for _, s := range statuses {
+		icon := "✅"
+		switch {
+		case s.LastRun.IsZero():
+			icon = "⏳"
+		case s.Failures > 0:
+			icon = "❌"
+		}
+		sb.WriteString(fmt.Sprintf("\n%s *%s* — %s\n", icon, escMD2(capitalize(s.Name)),
+			escMD2(fmt.Sprintf("%d/%d/%d", s.Fetched, s.Parsed, s.Kept))))
+		if s.LastSuccess.IsZero() {
+			sb.WriteString(escMD2("  успешных опросов ещё не было") + "\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("  ✔️ %s\n", escMD2("успех "+fmtAgo(now.Sub(s.LastSuccess)))))
+		}
+		if s.Endpoint != "" {
+			sb.WriteString(fmt.Sprintf("  🌐 %s\n", escMD2(endpointHost(s.Endpoint))))
+		}
+		if s.LastError != "" {
+			sb.WriteString(fmt.Sprintf("  ⚠️ %s\n", escMD2(fmt.Sprintf("%s (подряд: %d)",
+				truncateTitle(s.LastError, 120), s.Failures))))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
+	sb.WriteString(escMD2("Счётчики: получено/разобрано/оставлено") + "\n")
+	return sb.String()
+}
+
+// FormatSourceDown формирует разовое предупреждение о недоступном источнике
+func FormatSourceDown(s calendar.SourceStatus) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚠️ *ИСТОЧНИК НЕДОСТУПЕН \\| %s*\n", escMD2(capitalize(s.Name))))
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+	sb.WriteString(escMD2(fmt.Sprintf("Неудачных обновлений подряд: %d", s.Failures)) + "\n")
+	if !s.LastSuccess.IsZero() {
+		sb.WriteString(escMD2("Последний успех: "+s.LastSuccess.Format("02 Jan 15:04 UTC")) + "\n")
+	}
+	if s.LastError != "" {
+		sb.WriteString(escMD2("Ошибка: "+truncateTitle(s.LastError, 200)) + "\n")
+	}
+	return sb.String()
+}
+
+// FormatUpdated формирует уведомление о переносе или пропаже события
+func FormatUpdated(e model.Event, changes []model.Change) string {
+	_, label := eventMeta(e)
 
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔄 *ОБНОВЛЕНО \\| %s*\n", escMD2(label)))
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("*%s* — %s\n", escMD2(e.Token), escMD2(e.Title)))
+	sb.WriteString(fmt.Sprintf("📍 %s\n", escMD2(fmtVenue(e))))
+	sb.WriteString("\n")
+	for _, c := range changes {
+		switch {
+		case c.Field == model.FieldStatus && c.New == model.StatusMissing:
+			sb.WriteString(escMD2("❌ Событие пропало из источника — возможно, отменено") + "\n")
+		case c.Field == model.FieldDate:
+			sb.WriteString(fmt.Sprintf("📅 %s\n", escMD2(fmt.Sprintf("Дата: %s → %s", c.Old, c.New))))
+		default:
+			sb.WriteString(fmt.Sprintf("✏️ %s\n", escMD2(fmt.Sprintf("%s: %s → %s",
+				changeFieldRu(c.Field), truncateTitle(c.Old, 80), truncateTitle(c.New, 80)))))
+		}
+	}
+	if e.URL != "" {
+		sb.WriteString(fmt.Sprintf("🔗 [Анонс](%s)\n", e.URL))
+	}
+	return sb.String()
+}
+
+func changeFieldRu(field string) string {
+	switch field {
+	case model.FieldTitle:
+		return "Заголовок"
+	case model.FieldDetails:
+		return "Детали"
+	case model.FieldStatus:
+		return "Статус"
+	}
+	return field
+}
+
+// unlockRowsLimit — сколько строк графика разлоков показывать в /unlock
+const unlockRowsLimit = 40
+
+// FormatUnlockSchedule формирует график разлоков токена для /unlock:
+// таблица по датам и категориям с накопленной долей предложения (Σ%).
+func FormatUnlockSchedule(token string, steps []calendar.UnlockStep, horizon time.Duration) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔓 *Разлоки %s*\n", escMD2(token)))
+	sb.WriteString(fmt.Sprintf("_%s_\n", escMD2("на "+fmtHorizon(horizon)+" вперёд")))
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+
+	if len(steps) == 0 {
+		sb.WriteString("\n" + escMD2("Разлоков в этом горизонте не найдено.") + "\n")
+		return sb.String()
+	}
+
+	// Таблица — в блоке кода: моноширинный шрифт, экранировать нечего
+	var rows []string
+	row := func(date, category, kind, pct, cum string) string {
+		return fmt.Sprintf("%-6s %-10s %-7s %6s %6s", date, category, kind, pct, cum)
+	}
+	rows = append(rows, row("Дата", "Кому", "Тип", "%", "Σ%"))
+	var usd float64
+	unknown := 0
+	for _, st := range steps {
+		date := fmtDate(st.Event.EventAt)
+		u := st.Event.Unlock
+		if u == nil || u.Percent == 0 {
+			unknown++
+		}
+		if u == nil || len(u.Allocations) == 0 {
+			pct := "?"
+			if u != nil && u.Percent > 0 {
+				pct = fmt.Sprintf("%.2f", u.Percent)
+			}
+			rows = append(rows, row(date, "—", "—", pct, fmt.Sprintf("%.2f", st.Cumulative)))
+			if u != nil {
+				usd += u.ValueUSD
+			}
+			continue
+		}
+		usd += u.ValueUSD
+		running := st.Cumulative - u.Percent
+		for i, a := range u.Allocations {
+			pct := "?"
+			if a.Percent > 0 {
+				pct = fmt.Sprintf("%.2f", a.Percent)
+				running += a.Percent
+			}
+			if i > 0 {
+				date = ""
+			}
+			rows = append(rows, row(date, unlockCategoryRu(a.Category), vestingKindRu(a.Kind), pct, fmt.Sprintf("%.2f", running)))
+		}
+	}
+	shown := rows
+	if len(rows) > unlockRowsLimit+1 {
+		shown = rows[:unlockRowsLimit+1]
+	}
+	sb.WriteString("```\n" + strings.Join(shown, "\n") + "\n```\n")
+	if len(shown) < len(rows) {
+		sb.WriteString(escMD2(fmt.Sprintf("… и ещё %d строк", len(rows)-len(shown))) + "\n")
+	}
+
+	last := steps[len(steps)-1]
+	total := fmt.Sprintf("Итого: %.2f%% supply", last.Cumulative)
+	if usd > 0 {
+		total += " (~" + fmtUSD(usd) + ")"
+	}
+	total += fmt.Sprintf(", дат с разлоками: %d", len(steps))
+	sb.WriteString(fmt.Sprintf("\n📊 %s\n", escMD2(total)))
+	if unknown > 0 {
+		sb.WriteString(escMD2(fmt.Sprintf("⚠️ Размер %d из них неизвестен — в Σ%% не входит", unknown)) + "\n")
+	}
+	return sb.String()
+}
+
+// unlockCategoryRu — категория держателей разлока для таблицы
+func unlockCategoryRu(c model.UnlockCategory) string {
+	switch c {
+	case model.UnlockTeam:
+		return "команда"
+	case model.UnlockInvestors:
+		return "инвесторы"
+	case model.UnlockEcosystem:
+		return "экосистема"
+	case model.UnlockOther:
+		return "прочее"
+	}
+	return "—"
+}
+
+// vestingKindRu — тип разлока для таблицы
+func vestingKindRu(k model.VestingKind) string {
+	switch k {
+	case model.VestingCliff:
+		return "клифф"
+	case model.VestingLinear:
+		return "линейно"
+	}
+	return "—"
+}
+
+// FormatStrategies перечисляет действующие стратегии для /strategies:
+// к каким событиям применимы, фильтры, параметры сделки и алерты.
+func FormatStrategies(list []strategy.Strategy, file string) string {
+	var sb strings.Builder
+	sb.WriteString("💡 *Стратегии*\n")
+	if file == "" {
+		file = "встроенные"
+	}
+	sb.WriteString(fmt.Sprintf("_%s_\n", escMD2(file)))
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+
+	for i, s := range list {
+		sb.WriteString(fmt.Sprintf("\n%d\\. *%s* `%s`\n", i+1, escMD2(s.Name), s.ID))
+		types := make([]string, len(s.Types))
+		for j, t := range s.Types {
+			types[j] = typeLabelRu(t)
+		}
+		applies := strings.Join(types, ", ")
+		if len(s.Subtypes) > 0 {
+			subtypes := make([]string, len(s.Subtypes))
+			for j, st := range s.Subtypes {
+				subtypes[j] = subtypeLabelRu(st)
+			}
+			applies += " · " + strings.Join(subtypes, ", ")
+		}
+		sb.WriteString(escMD2("События: "+applies) + "\n")
+
+		filters := make([]string, len(s.Filters))
+		for j, f := range s.Filters {
+			filters[j] = fmtFilter(f)
+		}
+		need := "все"
+		if s.MinPass > 0 {
+			need = fmt.Sprintf("%d из %d", s.MinPass, len(s.Filters))
+		}
+		sb.WriteString(escMD2(fmt.Sprintf("Фильтры (%s): %s", need, strings.Join(filters, "; "))) + "\n")
+		if pos := fmtPosition(s.Position); pos != "" {
+			sb.WriteString(escMD2("Сделка: "+pos) + "\n")
+		}
+		if s.Hint != "" {
+			sb.WriteString(escMD2("Вход: "+s.Hint) + "\n")
+		}
+		alerts := s.Alerts
+		if len(alerts) == 0 {
+			alerts = strategy.Alerts
+		}
+		leads := make([]string, len(alerts))
+		for j, a := range alerts {
+			leads[j] = "за " + strings.TrimSuffix(string(a), "h") + "ч"
+		}
+		sb.WriteString(escMD2("Алерты: "+strings.Join(leads, ", ")) + "\n")
+		for _, item := range s.Manual {
+			sb.WriteString(fmt.Sprintf("  ☐ %s\n", escMD2(item)))
+		}
+	}
+	return sb.String()
+}
+
+// FormatStrategiesInvalid предупреждает, что изменённый файл стратегий не
+// применён
+func FormatStrategiesInvalid(err error) string {
+	var sb strings.Builder
+	sb.WriteString("⚠️ *СТРАТЕГИИ НЕ ОБНОВЛЕНЫ*\n")
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+	sb.WriteString(escMD2(truncateTitle(err.Error(), 300)) + "\n")
+	sb.WriteString(escMD2("Бот продолжает работать с прежними стратегиями.") + "\n")
+	return sb.String()
+}
+
+// FormatStorageRecovered предупреждает, что кэш событий был повреждён и
+// восстановлен из резервной копии
+func FormatStorageRecovered(err error) string {
+	var sb strings.Builder
+	sb.WriteString("⚠️ *КЭШ СОБЫТИЙ ПОВРЕЖДЁН*\n")
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+	sb.WriteString(escMD2(truncateTitle(err.Error(), 300)) + "\n")
+	sb.WriteString(escMD2("События загружены из резервной копии: алерты, отправленные после неё, могут прийти повторно.") + "\n")
+	return sb.String()
+}
+
+// endpointHost оставляет от URL эндпоинта только хост — путь в /status не нужен
+func endpointHost(u string) string {
+	if i := strings.Index(u, "://"); i != -1 {
+		u = u[i+3:]
+	}
+	if i := strings.IndexByte(u, '/'); i != -1 {
+		u = u[:i]
+	}
+	return u
+}
+
+// fmtAgo форматирует прошедшее время: "5 мин назад", "3 ч назад"
+func fmtAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "только что"
+	case d < time.Hour:
+		return fmt.Sprintf("%d мин назад", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d ч назад", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d дн назад", int(d.Hours()/24))
+}
+
+// historyLimit — сколько последних событий показывает /history
+const historyLimit = 30
+
+// FormatHistory renders past events of a token for the /history command,
+// newest first. events are expected in chronological order.
+func FormatHistory(token string, events []model.Event) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🗂 *История %s*\n", escMD2(token)))
+	sb.WriteString(fmt.Sprintf("%s\n", escMD2(separator)))
+
+	if len(events) == 0 {
+		sb.WriteString("\n" + escMD2("Прошедших событий не найдено.") + "\n")
+		return sb.String()
+	}
+
+	shown := events
+	if len(shown) > historyLimit {
+		shown = shown[len(shown)-historyLimit:]
+	}
+	for i := len(shown) - 1; i >= 0; i-- {
+		e := shown[i]
+		sb.WriteString(fmt.Sprintf("\n%s *%s* — %s\n",
+			eventIcon(e.Type),
+			escMD2(fmt.Sprintf("%s %d", fmtDate(e.EventAt), e.EventAt.Year())),
+			escMD2(typeLabelRu(e.Type))))
+		if e.Title != "" && e.Title != e.Token {
+			sb.WriteString(fmt.Sprintf("  %s\n", escMD2(truncateTitle(e.Title, 80))))
+		}
+		sb.WriteString(fmt.Sprintf("  📍 %s\n", escMD2(fmtVenue(e))))
+		if e.Details != "" {
+			sb.WriteString(fmt.Sprintf("  ℹ️ %s\n", escMD2(e.Details)))
+		}
+		if o := e.Outcome; o != nil && !o.NoMarket && o.PriceAt > 0 {
+			sb.WriteString(fmt.Sprintf("  📈 %s\n", escMD2(fmtOutcome(*o))))
+		}
+		if e.URL != "" {
+			sb.WriteString(fmt.Sprintf("  🔗 [Подробнее](%s)\n", e.URL))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
+	total := escMD2(fmt.Sprintf("%d %s", len(events), pluralEvents(len(events))))
+	if len(events) > len(shown) {
+		sb.WriteString(fmt.Sprintf("📊 %s, %s\n", total, escMD2(fmt.Sprintf("показаны последние %d", len(shown)))))
+	} else {
+		sb.WriteString(fmt.Sprintf("📊 %s\n", total))
+	}
+	return sb.String()
+}
+
+// fmtTokenomics — токеномика одной строкой: MCap $1.2B · FDV $15B (×12.5) · float 8%
+func fmtTokenomics(t model.Tokenomics) string {
+	var parts []string
+	if t.MarketCap > 0 {
+		parts = append(parts, "MCap "+fmtUSD(t.MarketCap))
+	}
+	if t.FDV > 0 {
+		fdv := "FDV " + fmtUSD(t.FDV)
+		if r := t.FDVRatio(); r > 0 {
+			fdv += fmt.Sprintf(" (×%.1f)", r)
+		}
+		parts = append(parts, fdv)
+	}
+	if t.FloatRatio > 0 {
+		parts = append(parts, fmt.Sprintf("float %.0f%%", t.FloatRatio*100))
+	}
+	if len(parts) == 0 {
+		return "токеномика неизвестна"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// fmtUSD — сумма в долларах с суффиксом: $950K, $350M, $1.2B
+func fmtUSD(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("$%.1fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("$%.0fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("$%.0fK", v/1e3)
+	}
+	return fmt.Sprintf("$%.0f", v)
+}
+
+// fmtOutcome — реакция цены одной строкой: 1h +3.1% · 24h −12.0% · max +15.2% / −20.4%
+func fmtOutcome(o model.Outcome) string {
+	var parts []string
+	for _, h := range model.OutcomeHorizons {
+		if r, ok := o.Return(h.Name); ok {
+			parts = append(parts, h.Name+" "+fmtPct(r))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("max %s / %s", fmtPct(o.MaxDrawup), fmtPct(o.MaxDrawdown)))
+	return strings.Join(parts, " · ")
+}
+
+// fmtPct — изменение в процентах со знаком
+func fmtPct(v float64) string {
+	return fmt.Sprintf("%+.1f%%", v)
+}
+
+// FormatOutcomeReport формирует еженедельный отчёт о реакции цены на события
+// [from, to): средние по типу события и источнику.
+func FormatOutcomeReport(stats []calendar.OutcomeStats, from, to time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📈 *РЕАКЦИЯ ЦЕНЫ НА СОБЫТИЯ*\n_%s — %s_\n",
+		escMD2(from.Format("02 Jan")), escMD2(to.Format("02 Jan 2006"))))
+
+	total := 0
+	for _, t := range typeOrder {
+		var group []calendar.OutcomeStats
+		for _, s := range stats {
+			if s.Type == t {
+				group = append(group, s)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s *%s*\n", eventIcon(t), escMD2(typeLabelRu(t))))
+		for _, s := range group {
+			total += s.Count
+			sb.WriteString(fmt.Sprintf("▸ *%s* \\(%d\\)\n", escMD2(capitalize(s.Source)), s.Count))
+			var parts []string
+			for _, h := range model.OutcomeHorizons {
+				if r, ok := s.AvgReturn[h.Name]; ok {
+					parts = append(parts, h.Name+" "+fmtPct(r))
+				}
+			}
+			if len(parts) > 0 {
+				sb.WriteString(fmt.Sprintf("  %s\n", escMD2(strings.Join(parts, " · "))))
+			}
+			sb.WriteString(fmt.Sprintf("  %s\n", escMD2(fmt.Sprintf("рост до %s, просадка до %s",
+				fmtPct(s.AvgDrawup), fmtPct(s.AvgDrawdown)))))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s\n", escMD2(separator)))
+	if total == 0 {
+		sb.WriteString(escMD2("Нет событий с известной ценой.") + "\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("📊 %s\n", escMD2(fmt.Sprintf("%d %s; средние значения, от цены на начало торгов",
+			total, pluralEvents(total)))))
+	}
 	return sb.String()
 }
 
@@ -206,11 +823,10 @@ func FormatEventList(events []model.Event, header string) string {
 	}
 
 	// Группируем по типу, если есть разные типы
-	types := []model.EventType{model.EventLaunchpool, model.EventListing, model.EventUnlock, model.EventAirdrop}
 	hasMultipleTypes := countTypes(events) > 1
 
-	for _, t := range types {
-		group := filterByType(events, t)
+	for _, t := range typeOrder {
+		group := bySeverity(filterByType(events, t))
 		if len(group) == 0 {
 			continue
 		}
@@ -228,12 +844,14 @@ func FormatEventList(events []model.Event, header string) string {
 				sb.WriteString(fmt.Sprintf(" — %s", escMD2(truncateTitle(e.Title, 80))))
 			}
 			sb.WriteString("\n")
-			sb.WriteString(fmt.Sprintf("  📅 %s UTC", escMD2(e.Date.UTC().Format("02 Jan, 15:04"))))
-			sb.WriteString(fmt.Sprintf("  📍 %s\n", escMD2(capitalize(e.Source))))
-			if e.Details != "" {
-				sb.WriteString(fmt.Sprintf("  ℹ️ %s\n", escMD2(e.Details)))
+			sb.WriteString(fmt.Sprintf("  📅 %s", escMD2(fmtWhen(e))))
+			sb.WriteString(fmt.Sprintf("  📍 %s\n", escMD2(fmtVenue(e))))
+			writeVenues(&sb, e, "  ")
+			writeDetails(&sb, e, "  ")
+			if e.Tokenomics != nil {
+				sb.WriteString(fmt.Sprintf("  💰 %s\n", escMD2(fmtTokenomics(*e.Tokenomics))))
 			}
-			if e.URL != "" {
+			if e.URL != "" && len(e.Venues) < 2 {
 				sb.WriteString(fmt.Sprintf("  🔗 [Подробнее](%s)\n", e.URL))
 			}
 		}
@@ -246,6 +864,40 @@ func FormatEventList(events []model.Event, header string) string {
 	return sb.String()
 }
 
+// writeDetails пишет доп. данные события; у разлока — с его уровнем:
+// "🔴 major: разлок 6% supply (~$80M)"
+func writeDetails(sb *strings.Builder, e model.Event, indent string) {
+	if u := e.Unlock; u != nil && u.Severity != "" {
+		details := e.Details
+		if details == "" {
+			details = "размер неизвестен"
+		}
+		icon := cmp.Or(u.Icon, "ℹ️")
+		sb.WriteString(fmt.Sprintf("%s%s _%s_: %s\n", indent, icon, escMD2(u.Severity), escMD2(details)))
+		return
+	}
+	if e.Details != "" {
+		sb.WriteString(fmt.Sprintf("%sℹ️ %s\n", indent, escMD2(e.Details)))
+	}
+}
+
+// bySeverity ставит серьёзные разлоки первыми, внутри уровня — по дате;
+// остальные события не переставляет
+func bySeverity(events []model.Event) []model.Event {
+	sort.SliceStable(events, func(i, j int) bool {
+		return unlockTier(events[i]) < unlockTier(events[j])
+	})
+	return events
+}
+
+// unlockTier — номер уровня разлока; 0 для остальных событий
+func unlockTier(e model.Event) int {
+	if e.Unlock == nil {
+		return 0
+	}
+	return e.Unlock.Tier
+}
+
 func eventIcon(t model.EventType) string {
 	switch t {
 	case model.EventLaunchpool:
@@ -256,6 +908,14 @@ func eventIcon(t model.EventType) string {
 		return "🔓"
 	case model.EventAirdrop:
 		return "🪂"
+	case model.EventDelisting:
+		return "📉"
+	case model.EventPremarket:
+		return "⏳"
+	case model.EventHodlerAirdrop:
+		return "🎁"
+	case model.EventMegadrop:
+		return "🎯"
 	}
 	return "📌"
 }
@@ -270,10 +930,28 @@ func typeLabelRu(t model.EventType) string {
 		return "Разлоки"
 	case model.EventAirdrop:
 		return "TGE / Airdrop"
+	case model.EventDelisting:
+		return "Делистинги"
+	case model.EventPremarket:
+		return "Pre-Market"
+	case model.EventHodlerAirdrop:
+		return "HODLer Airdrops"
+	case model.EventMegadrop:
+		return "Megadrop"
 	}
 	return string(t)
 }
 
+func subtypeLabelRu(s model.EventSubtype) string {
+	switch s {
+	case model.SubtypeSpotListing:
+		return "спот"
+	case model.SubtypeFuturesLaunch:
+		return "фьючерсы"
+	}
+	return string(s)
+}
+
 func countTypes(events []model.Event) int {
 	seen := make(map[model.EventType]struct{})
 	for _, e := range events {
@@ -4,18 +4,19 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
 const (
-	airdropsSource = "airdrops"
-	airdropsRSSURL = "https://airdrops.io/feed/"
+	airdropsSource  = "airdrops"
+	airdropsBaseURL = "https://airdrops.io"
+	airdropsRSSPath = "/feed/"
 )
 
 // rssFeed represents the top-level RSS document.
@@ -39,28 +40,38 @@ type rssItem struct {
 
 // AirdropsScanner fetches airdrop events from the airdrops.io RSS feed.
 type AirdropsScanner struct {
-	client *http.Client
+	fetcher *fetcher
+	bases   []string // primary host and mirrors
+	window  config.Window
 }
 
 // NewAirdropsScanner constructs an AirdropsScanner with a sensible HTTP client.
-func NewAirdropsScanner() *AirdropsScanner {
+// opts.BaseURL and opts.Transport let tests point it at recorded fixtures.
+func NewAirdropsScanner(opts Options) *AirdropsScanner {
 	return &AirdropsScanner{
-		client: &http.Client{Timeout: 15 * time.Second},
+		fetcher: newFetcher(opts.client(), http.Header{
+			"Accept":     {"application/rss+xml, application/xml, text/xml"},
+			"User-Agent": {binanceUserAgent},
+		}),
+		bases:  opts.baseURLs(airdropsBaseURL),
+		window: opts.scanWindow(),
 	}
 }
 
-// Scan fetches the airdrops.io RSS feed and returns events within the next 7 days.
-// If the feed is unavailable the scanner logs a warning and returns an empty
-// (non-nil) slice — callers should treat this as a graceful degradation.
-func (s *AirdropsScanner) Scan(ctx context.Context) ([]model.Event, error) {
-	// Окно: статьи опубликованы за последние 14 дней (дата публикации = дата события)
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	horizon := time.Now().UTC().Add(7 * 24 * time.Hour)
+func init() {
+	Register(airdropsSource, func(opts Options) Scanner { return NewAirdropsScanner(opts) })
+}
+
+// Scan fetches the airdrops.io RSS feed and returns events within the configured window.
+// If the feed is unavailable the error is returned so the aggregator can tell
+// a broken source from an empty feed.
+func (s *AirdropsScanner) Scan(ctx context.Context) (Result, error) {
+	// Окно: статьи опубликованы за lookback (дата публикации = дата события)
+	from, horizon := s.window.Range(time.Now().UTC())
 
-	items, err := s.fetchFeed(ctx)
+	items, served, err := s.fetchFeed(ctx)
 	if err != nil {
-		log.Printf("[airdrops] warning: failed to fetch RSS feed: %v", err)
-		return []model.Event{}, nil
+		return Result{}, fmt.Errorf("airdrops: fetch RSS feed: %w", err)
 	}
 
 	var events []model.Event
@@ -72,39 +83,22 @@ func (s *AirdropsScanner) Scan(ctx context.Context) ([]model.Event, error) {
 		events = append(events, ev)
 	}
 
-	return events, nil
+	return Result{Events: events, Fetched: len(items), Endpoint: served}, nil
 }
 
-// fetchFeed performs the HTTP GET and parses the RSS XML from airdrops.io.
-func (s *AirdropsScanner) fetchFeed(ctx context.Context) ([]rssItem, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, airdropsRSSURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set("Accept", "application/rss+xml, application/xml, text/xml")
-	req.Header.Set("User-Agent", binanceUserAgent)
-
-	resp, err := s.client.Do(req)
+// fetchFeed fetches the RSS XML from airdrops.io (or a mirror) and parses it.
+func (s *AirdropsScanner) fetchFeed(ctx context.Context) ([]rssItem, string, error) {
+	body, served, err := s.fetcher.fetch(ctx, endpoints(s.bases, airdropsRSSPath))
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d from airdrops.io", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20)) // 2 MB cap
-	if err != nil {
-		return nil, fmt.Errorf("read body: %w", err)
+		return nil, "", err
 	}
 
 	var feed rssFeed
 	if err := xml.Unmarshal(body, &feed); err != nil {
-		return nil, fmt.Errorf("parse XML: %w", err)
+		return nil, "", fmt.Errorf("parse XML from %s: %w", served, err)
 	}
 
-	return feed.Channel.Items, nil
+	return feed.Channel.Items, served, nil
 }
 
 // parseRSSItem converts a raw RSS item into a model.Event.
@@ -127,20 +121,33 @@ func parseRSSItem(item rssItem, now, horizon time.Time) (model.Event, bool) {
 
 	token := extractTokenFromTitle(item.Title)
 	if token == "" {
-		token = "UNKNOWN"
+		token = model.UnknownToken
 	}
 
 	details := cleanDescription(item.Description)
 
+	// ID — по ссылке на статью; без ссылки — по дате публикации
+	link := strings.TrimSpace(item.Link)
+	id, group := makeEventID(airdropsSource, token, eventDate), ""
+	if link != "" {
+		group = articleGroup(airdropsSource, articleRef(link))
+		id = makeArticleID(group, token)
+	}
+
 	return model.Event{
-		ID:      makeEventID(airdropsSource, token, eventDate),
+		ID:      id,
 		Type:    model.EventAirdrop,
 		Source:  airdropsSource,
 		Token:   strings.ToUpper(token),
 		Title:   strings.TrimSpace(item.Title),
-		Date:    eventDate,
-		URL:     strings.TrimSpace(item.Link),
+		EventAt: eventDate,
+		URL:     link,
 		Details: details,
+		GroupID: group,
+
+		// RSS has no event date: the publish date stands in for it
+		AnnouncedAt:    eventDate,
+		DateConfidence: model.DateInferred,
 	}, true
 }
 
@@ -168,19 +175,5 @@ func parseRSSDate(s string) (time.Time, error) {
 // cleanDescription strips HTML tags and trims whitespace from a description
 // string so it can be stored as plain text in Details.
 func cleanDescription(s string) string {
-	// Simple HTML tag removal — adequate for RSS descriptions.
-	var out strings.Builder
-	inTag := false
-	for _, r := range s {
-		switch {
-		case r == '<':
-			inTag = true
-		case r == '>':
-			inTag = false
-		case !inTag:
-			out.WriteRune(r)
-		}
-	}
-	result := strings.Join(strings.Fields(out.String()), " ")
-	return truncate(result, 200)
+	return truncate(stripHTML(s), 200)
 }
@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,15 +11,19 @@ import (
 	"strings"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
 const (
 	binanceSource      = "binance"
 	binanceUserAgent   = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
-	binanceListingURL  = "https://www.binance.com/bapi/composite/v1/public/cms/article/list/query?type=1&pageNo=1&pageSize=20&catalogId=48"
-	binanceLaunchURL   = "https://www.binance.com/bapi/composite/v1/public/cms/article/list/query?type=1&pageNo=1&pageSize=20&catalogId=161"
+	binanceBaseURL     = "https://www.binance.com"
+	binanceListPath    = "/bapi/composite/v1/public/cms/article/list/query?type=1&pageNo=%d&pageSize=20&catalogId=%d"
+	binanceListingCat  = 48  // New Cryptocurrency Listing
+	binanceLaunchCat   = 161 // Launchpool / Megadrop
 	binanceArticleBase = "https://www.binance.com/en/support/announcement/"
+	binanceDetailPath  = "/bapi/composite/v1/public/cms/article/detail/query?articleCode=%s"
 )
 
 // Ищем дату в конце заголовка вида "(2026-02-21)"
@@ -35,6 +40,14 @@ type binanceResponse struct {
 	} `json:"data"`
 }
 
+// binanceDetailResponse — ответ detail API: body содержит текст анонса
+// (JSON-дерево rich text, строкой)
+type binanceDetailResponse struct {
+	Data struct {
+		Body string `json:"body"`
+	} `json:"data"`
+}
+
 type binanceArticle struct {
 	ID          int64  `json:"id"`
 	Code        string `json:"code"`
@@ -43,60 +56,103 @@ type binanceArticle struct {
 }
 
 type BinanceScanner struct {
-	client *http.Client
+	fetcher  *fetcher
+	bases    []string // основной хост и зеркала
+	maxPages int
+	window   config.Window
+
+	withDetails bool
+	details     *detailCache
+	rules       *Classifier
 }
 
-func NewBinanceScanner() *BinanceScanner {
+func NewBinanceScanner(opts Options) *BinanceScanner {
 	return &BinanceScanner{
-		client: &http.Client{Timeout: 15 * time.Second},
+		fetcher: newFetcher(opts.client(), http.Header{
+			"User-Agent": {binanceUserAgent},
+			"Accept":     {"application/json"},
+			// НЕ ставим Accept-Encoding: gzip — Go http.Client сам обрабатывает сжатие
+		}),
+		bases:    opts.baseURLs(binanceBaseURL),
+		maxPages: opts.MaxPages,
+		window:   opts.scanWindow(),
+
+		withDetails: opts.FetchDetails,
+		details:     newDetailCache(),
+		rules:       opts.classifier(),
 	}
 }
 
-func (s *BinanceScanner) Scan(ctx context.Context) ([]model.Event, error) {
-	// Окно: анонсы за последние 14 дней (биржи анонсируют за 7-14 дней) и на 7 дней вперёд
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	to := time.Now().UTC().Add(7 * 24 * time.Hour)
+func init() {
+	Register(binanceSource, func(opts Options) Scanner { return NewBinanceScanner(opts) })
+}
 
-	var events []model.Event
-	for _, endpoint := range []string{binanceListingURL, binanceLaunchURL} {
-		articles, err := s.fetchArticles(ctx, endpoint)
+func (s *BinanceScanner) Scan(ctx context.Context) (Result, error) {
+	// Окно: анонсы за lookback (биржи анонсируют за 7-14 дней) и на horizon вперёд
+	from, to := s.window.Range(time.Now().UTC())
+
+	var (
+		res    Result
+		events []model.Event
+		errs   []error
+	)
+	catalogs := []int{binanceListingCat, binanceLaunchCat}
+	for _, catalog := range catalogs {
+		articles, served, err := walkPages(ctx, binanceSource, s.maxPages, from,
+			func(ctx context.Context, page int) ([]binanceArticle, string, error) {
+				return s.fetchArticles(ctx, catalog, page)
+			},
+			func(a binanceArticle) time.Time { return time.UnixMilli(a.ReleaseDate) })
 		if err != nil {
 			log.Printf("[binance] warning: %v", err)
+			errs = append(errs, err)
 			continue
 		}
+		res.Fetched += len(articles)
+		res.Endpoint = served
 		for _, a := range articles {
-			ev, ok := s.parseArticle(a, from, to)
-			if !ok {
-				continue
-			}
-			events = append(events, ev)
+			events = append(events, s.parseArticle(a, from, to)...)
 		}
 	}
-	return deduplicateEvents(events), nil
+	// Ошибка — только если не ответил ни один каталог
+	if len(errs) == len(catalogs) {
+		return Result{}, errors.Join(errs...)
+	}
+	events = deduplicateEvents(events)
+	if s.withDetails {
+		events = refineEventTimes(ctx, binanceSource, events, s.details, s.fetchArticleBody)
+	}
+	res.Events = events
+	return res, nil
 }
 
-func (s *BinanceScanner) fetchArticles(ctx context.Context, url string) ([]binanceArticle, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
+// fetchArticleBody загружает текст анонса через detail API по коду статьи из URL
+func (s *BinanceScanner) fetchArticleBody(ctx context.Context, url string) (string, error) {
+	code := strings.TrimPrefix(url, binanceArticleBase)
+	if code == url || code == "" {
+		return "", fmt.Errorf("not a binance article url: %s", url)
 	}
-	req.Header.Set("User-Agent", binanceUserAgent)
-	req.Header.Set("Accept", "application/json")
-	// НЕ ставим Accept-Encoding: gzip — Go http.Client сам обрабатывает сжатие
-
-	resp, err := s.client.Do(req)
+	body, served, err := s.fetcher.fetch(ctx, endpoints(s.bases, fmt.Sprintf(binanceDetailPath, code)))
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
+	var parsed binanceDetailResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("decode %s: %w", served, err)
+	}
+	return parsed.Data.Body, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d", resp.StatusCode)
+func (s *BinanceScanner) fetchArticles(ctx context.Context, catalog, page int) ([]binanceArticle, string, error) {
+	path := fmt.Sprintf(binanceListPath, page, catalog)
+	body, served, err := s.fetcher.fetch(ctx, endpoints(s.bases, path))
+	if err != nil {
+		return nil, "", err
 	}
 
 	var parsed binanceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", served, err)
 	}
 	// Собираем статьи из всех каталогов
 	var articles []binanceArticle
@@ -104,67 +160,51 @@ func (s *BinanceScanner) fetchArticles(ctx context.Context, url string) ([]binan
 	for _, cat := range parsed.Data.Catalogs {
 		articles = append(articles, cat.Articles...)
 	}
-	return articles, nil
+	return articles, served, nil
 }
 
-func (s *BinanceScanner) parseArticle(a binanceArticle, from, to time.Time) (model.Event, bool) {
+func (s *BinanceScanner) parseArticle(a binanceArticle, from, to time.Time) []model.Event {
 	// releaseDate — дата публикации анонса (мс)
 	announceDate := time.Unix(a.ReleaseDate/1000, 0).UTC()
 
-	// Пробуем извлечь дату события из заголовка (формат "(2026-02-21)")
-	eventDate := announceDate
+	// Пробуем извлечь дату события из заголовка (формат "(2026-02-21)"),
+	// иначе дата события неизвестна и условно равна дате анонса
+	eventDate, confidence := announceDate, model.DateInferred
 	if m := binanceTitleDateRe.FindStringSubmatch(a.Title); len(m) == 2 {
 		if parsed, err := time.Parse("2006-01-02", m[1]); err == nil {
-			eventDate = parsed.UTC()
+			eventDate, confidence = parsed.UTC(), model.DateOnly
 		}
 	}
 
 	// Включаем если дата анонса или дата события попадает в окно
 	if announceDate.Before(from) && eventDate.Before(from) {
-		return model.Event{}, false
+		return nil
 	}
 	if announceDate.After(to) && eventDate.After(to) {
-		return model.Event{}, false
+		return nil
 	}
 
-	eventType, ok := classifyBinanceTitle(a.Title)
+	class, ok := s.rules.Classify(binanceSource, a.Title)
 	if !ok {
-		return model.Event{}, false
+		return nil
 	}
 
-	token := extractTokenFromParentheses(a.Title)
-	if token == "" {
-		token = extractTokenFromTitle(a.Title)
-	}
-	if token == "" {
-		token = "UNKNOWN"
+	// Служебные анонсы (фиатные пары, региональная поддержка) — не листинги
+	if classifyNotice(a.Title) != "" {
+		return nil
 	}
 
-	return model.Event{
-		ID:     makeEventID(binanceSource, token, eventDate),
-		Type:   eventType,
-		Source: binanceSource,
-		Token:  strings.ToUpper(token),
-		Title:  a.Title,
-		Date:   eventDate,
-		URL:    binanceArticleBase + a.Code,
-	}, true
-}
-
-func classifyBinanceTitle(title string) (model.EventType, bool) {
-	upper := strings.ToUpper(title)
-	// Исключаем делистинги, уведомления, апдейты
-	if strings.Contains(upper, "DELIST") || strings.Contains(upper, "REMOVAL") ||
-		strings.Contains(upper, "NOTICE ON") || strings.Contains(upper, "SUSPEND") {
-		return "", false
-	}
-	switch {
-	case strings.Contains(upper, "LAUNCHPOOL"):
-		return model.EventLaunchpool, true
-	case strings.Contains(upper, "WILL LIST") || strings.Contains(upper, "WILL LAUNCH") ||
-		strings.Contains(upper, "NEW LISTING") || strings.Contains(upper, "PERPETUAL"):
-		return model.EventListing, true
-	default:
-		return "", false
-	}
+	return expandTokens(model.Event{
+		Type:    class.Type,
+		Subtype: class.Subtype,
+		Source:  binanceSource,
+		Title:   a.Title,
+		EventAt: eventDate,
+		URL:     binanceArticleBase + a.Code,
+
+		AnnouncedAt:    announceDate,
+		DateConfidence: confidence,
+		GroupID:        articleGroup(binanceSource, a.Code),
+		Rule:           class.Rule,
+	}, extractTokensFromTitle(a.Title))
 }
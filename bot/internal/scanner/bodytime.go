@@ -0,0 +1,274 @@
+package scanner
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// Заголовки бирж обычно несут только дату, а точное время открытия торгов
+// есть лишь в тексте анонса: "trading opens at 2026-02-21 10:00 (UTC)",
+// "Feb 21, 2026, 18:00 (UTC+8)". Здесь — разбор такого текста и кэш
+// загруженных страниц, чтобы каждый анонс качался один раз.
+
+// tzPart — "(UTC)", "UTC+8", "(UTC+08:00)", "GMT-3"
+const tzPart = `\s*\(?\s*(?:UTC|GMT)\s*([+-]\s*\d{1,2}(?::?\d{2})?)?\s*\)?`
+
+var (
+	// 2026-02-21 10:00 (UTC) / 2026-02-21 at 10:00:00 UTC+8
+	reBodyISODateTime = regexp.MustCompile(`(?i)(\d{4})-(\d{2})-(\d{2})[ T]+(?:at\s+)?(\d{1,2}):(\d{2})(?::\d{2})?` + tzPart)
+	// 10:00 (UTC) on 2026-02-21
+	reBodyISOTimeDate = regexp.MustCompile(`(?i)(\d{1,2}):(\d{2})(?::\d{2})?` + tzPart + `\s*(?:on\s+)?(\d{4})-(\d{2})-(\d{2})`)
+	// February 21, 2026, 10:00 AM (UTC) / Feb 21 at 18:00 UTC+8
+	reBodyEnglish = regexp.MustCompile(`(?i)\b(Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:t(?:ember)?)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s*(\d{4})?,?\s*(?:at\s+)?(\d{1,2}):(\d{2})\s*(AM|PM)?` + tzPart)
+)
+
+// bodyKeywords — слова перед датой, по которым отличаем время начала торгов
+// от времени открытия депозитов и прочих дат анонса. Больший вес — лучше.
+var bodyKeywords = []struct {
+	word   string
+	weight int
+}{
+	{"trading", 3}, {"trade", 3},
+	{"list", 2}, {"launch", 2},
+	{"open", 1}, {"start", 1}, {"begin", 1},
+}
+
+// bodyTimeMatch — одно найденное в тексте время
+type bodyTimeMatch struct {
+	at    time.Time
+	pos   int
+	score int
+}
+
+// extractEventTimeFromBody ищет в тексте анонса дату со временем и часовым
+// поясом UTC/GMT (со смещением или без) и возвращает её в UTC. Время без
+// указания пояса игнорируется — угадывать пояс хуже, чем не знать времени.
+// ref — дата публикации: подставляет год, если он не указан, и отсекает
+// заведомо посторонние даты (раньше анонса или дальше чем через 60 дней).
+func extractEventTimeFromBody(body string, ref time.Time) (time.Time, bool) {
+	var found []bodyTimeMatch
+
+	for _, m := range reBodyISODateTime.FindAllStringSubmatchIndex(body, -1) {
+		g := submatches(body, m)
+		if t, ok := buildBodyTime(g[1], g[2], g[3], g[4], g[5], "", g[6], ref); ok {
+			found = append(found, bodyTimeMatch{at: t, pos: m[0]})
+		}
+	}
+	for _, m := range reBodyISOTimeDate.FindAllStringSubmatchIndex(body, -1) {
+		g := submatches(body, m)
+		if t, ok := buildBodyTime(g[4], g[5], g[6], g[1], g[2], "", g[3], ref); ok {
+			found = append(found, bodyTimeMatch{at: t, pos: m[0]})
+		}
+	}
+	for _, m := range reBodyEnglish.FindAllStringSubmatchIndex(body, -1) {
+		g := submatches(body, m)
+		month, ok := monthNames[strings.ToLower(strings.TrimSuffix(g[1], "."))]
+		if !ok {
+			month, ok = monthNames[strings.ToLower(g[1][:3])]
+		}
+		if !ok {
+			continue
+		}
+		if t, ok := buildBodyTime(g[3], strconv.Itoa(int(month)), g[2], g[4], g[5], g[6], g[7], ref); ok {
+			found = append(found, bodyTimeMatch{at: t, pos: m[0]})
+		}
+	}
+	if len(found) == 0 {
+		return time.Time{}, false
+	}
+
+	best := -1
+	for i := range found {
+		start := found[i].pos - 150
+		if start < 0 {
+			start = 0
+		}
+		// Позиции — в байтах body: нижний регистр берём только у окна перед
+		// совпадением, ToLower всего текста может изменить его длину (K → k)
+		before := strings.ToLower(body[start:found[i].pos])
+		for _, kw := range bodyKeywords {
+			if strings.Contains(before, kw.word) && kw.weight > found[i].score {
+				found[i].score = kw.weight
+			}
+		}
+		if best == -1 || found[i].score > found[best].score ||
+			(found[i].score == found[best].score && found[i].pos < found[best].pos) {
+			best = i
+		}
+	}
+	return found[best].at, true
+}
+
+// submatches возвращает группы совпадения строками ("" для пропущенных)
+func submatches(s string, idx []int) []string {
+	out := make([]string, len(idx)/2)
+	for i := range out {
+		if idx[2*i] >= 0 {
+			out[i] = s[idx[2*i]:idx[2*i+1]]
+		}
+	}
+	return out
+}
+
+// buildBodyTime собирает время из строковых частей и переводит его в UTC.
+// offset — "+8", "-03:00", "+0530" или пусто для чистого UTC.
+func buildBodyTime(year, month, day, hour, minute, ampm, offset string, ref time.Time) (time.Time, bool) {
+	y := ref.Year()
+	if year != "" {
+		y, _ = strconv.Atoi(year)
+	}
+	mo, err1 := strconv.Atoi(month)
+	d, err2 := strconv.Atoi(day)
+	h, err3 := strconv.Atoi(hour)
+	mi, err4 := strconv.Atoi(minute)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil ||
+		mo < 1 || mo > 12 || d < 1 || d > 31 || h > 23 || mi > 59 {
+		return time.Time{}, false
+	}
+	switch strings.ToUpper(ampm) {
+	case "PM":
+		if h < 12 {
+			h += 12
+		}
+	case "AM":
+		if h == 12 {
+			h = 0
+		}
+	}
+
+	offsetMin, ok := parseUTCOffset(offset)
+	if !ok {
+		return time.Time{}, false
+	}
+	t := time.Date(y, time.Month(mo), d, h, mi, 0, 0, time.UTC).Add(-time.Duration(offsetMin) * time.Minute)
+
+	// Год не указан, а дата вышла в прошлом относительно анонса — это следующий год
+	if year == "" && t.Before(ref.Add(-24*time.Hour)) {
+		t = t.AddDate(1, 0, 0)
+	}
+	if t.Before(ref.Add(-24*time.Hour)) || t.After(ref.Add(60*24*time.Hour)) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseUTCOffset разбирает смещение "+8", "-03:00", "+0530" в минуты.
+func parseUTCOffset(s string) (int, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, true
+	}
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	s = strings.ReplaceAll(s[1:], ":", "")
+	var h, m int
+	var err error
+	switch {
+	case len(s) <= 2:
+		h, err = strconv.Atoi(s)
+	case len(s) == 3 || len(s) == 4:
+		h, err = strconv.Atoi(s[:len(s)-2])
+		if err == nil {
+			m, err = strconv.Atoi(s[len(s)-2:])
+		}
+	default:
+		return 0, false
+	}
+	if err != nil || h > 14 || m > 59 {
+		return 0, false
+	}
+	return sign * (h*60 + m), true
+}
+
+// maxDetailFetches — сколько страниц анонсов качать за один проход сканера;
+// остальные подтянутся при следующих обновлениях.
+const maxDetailFetches = 10
+
+// detailCache помнит результат разбора страницы анонса по её ключу (URL),
+// чтобы каждая страница загружалась один раз за время жизни процесса.
+type detailCache struct {
+	mu sync.Mutex
+	m  map[string]detailTime
+}
+
+type detailTime struct {
+	at time.Time
+	ok bool // время найдено
+}
+
+func newDetailCache() *detailCache {
+	return &detailCache{m: make(map[string]detailTime)}
+}
+
+func (c *detailCache) get(key string) (detailTime, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dt, ok := c.m[key]
+	return dt, ok
+}
+
+func (c *detailCache) put(key string, dt detailTime) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[key] = dt
+}
+
+// refineEventTimes уточняет время событий без точного времени по тексту их
+// анонса. fetchBody загружает текст анонса по URL события. Ошибки загрузки не
+// кэшируются — страница будет запрошена снова при следующем обновлении.
+func refineEventTimes(ctx context.Context, source string, events []model.Event, cache *detailCache,
+	fetchBody func(ctx context.Context, url string) (string, error)) []model.Event {
+	fetched := 0
+	for i, e := range events {
+		if e.Confidence() == model.DateExact || e.URL == "" {
+			continue
+		}
+		dt, cached := cache.get(e.URL)
+		if !cached {
+			if fetched >= maxDetailFetches || ctx.Err() != nil {
+				continue
+			}
+			fetched++
+			body, err := fetchBody(ctx, e.URL)
+			if err != nil {
+				log.Printf("[%s] warning: detail %s: %v", source, e.URL, err)
+				continue
+			}
+			ref := e.AnnouncedAt
+			if ref.IsZero() {
+				ref = e.EventAt
+			}
+			dt.at, dt.ok = extractEventTimeFromBody(stripHTML(body), ref)
+			cache.put(e.URL, dt)
+		}
+		if !dt.ok {
+			continue
+		}
+		events[i].EventAt = dt.at
+		events[i].DateConfidence = model.DateExact
+		if e.GroupID == "" {
+			events[i].ID = makeEventID(e.Source, e.Token, dt.at)
+		}
+	}
+	return events
+}
+
+// fetchPageBody возвращает загрузчик HTML-страницы анонса по её URL
+func fetchPageBody(pages *fetcher) func(ctx context.Context, url string) (string, error) {
+	return func(ctx context.Context, url string) (string, error) {
+		body, _, err := pages.fetch(ctx, []string{url})
+		if err != nil {
+			return "", err
+		}
+		return string(body), nil
+	}
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractEventTimeFromBody(t *testing.T) {
+	ref := utc("2026-02-18T08:00:00Z")
+	tests := []struct {
+		name string
+		body string
+		ref  time.Time
+		want string // "" — времени нет
+	}{
+		{"iso utc", "Binance will open trading for PENGU/USDT at 2026-02-21 10:00 (UTC).", ref, "2026-02-21T10:00:00Z"},
+		{"iso utc+8", "Trading starts: 2026-02-21 18:00 (UTC+8)", ref, "2026-02-21T10:00:00Z"},
+		{"iso utc+08:00 with seconds", "Spot trading will begin at 2026-02-21 18:30:00 UTC+08:00", ref, "2026-02-21T10:30:00Z"},
+		{"gmt-3", "Listing time: 2026-02-21 07:00 GMT-3", ref, "2026-02-21T10:00:00Z"},
+		{"time before date", "Trading opens at 10:00 (UTC) on 2026-02-21.", ref, "2026-02-21T10:00:00Z"},
+		{"english am", "Trading begins on February 21, 2026, 10:00 AM (UTC).", ref, "2026-02-21T10:00:00Z"},
+		{"english pm", "Trading for the OPN/USDT pair opens Feb 21 at 6:30 PM UTC", ref, "2026-02-21T18:30:00Z"},
+		{"english 12 am", "Trading starts Feb. 22nd, 12:00 AM (UTC)", ref, "2026-02-22T00:00:00Z"},
+		{"english utc+8", "Trading launches Feb 21, 2026, 18:00 (UTC+8)", ref, "2026-02-21T10:00:00Z"},
+		{"year rollover", "Trading starts Jan 3 at 09:00 UTC.", utc("2025-12-28T12:00:00Z"), "2026-01-03T09:00:00Z"},
+		{"trading beats deposits",
+			"Deposits open at 2026-02-20 08:00 (UTC). Trading starts at 2026-02-21 10:00 (UTC). Withdrawals open at 2026-02-22 10:00 (UTC).",
+			ref, "2026-02-21T10:00:00Z"},
+		{"no timezone", "Trading opens at 2026-02-21 10:00.", ref, ""},
+		{"before announcement", "Trading opened at 2026-01-05 10:00 (UTC).", ref, ""},
+		{"too far ahead", "Trading opens at 2026-06-01 10:00 (UTC).", ref, ""},
+		{"cjk", "交易开始时间：2026-02-21 18:00 (UTC+8)，充值已开放。", ref, "2026-02-21T10:00:00Z"},
+		// ToLower("K") — однобайтная "k": длина текста меняется
+		{"kelvin sign", strings.Repeat("K", 200) + " Trading opens at 2026-02-21 10:00 (UTC).", ref, "2026-02-21T10:00:00Z"},
+		{"kelvin after match", "Trading opens at 2026-02-21 10:00 (UTC). " + strings.Repeat("K", 200), ref, "2026-02-21T10:00:00Z"},
+		{"cut rune in window", strings.Repeat("Ж", 100) + "trade 2026-02-21 10:00 UTC", ref, "2026-02-21T10:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := extractEventTimeFromBody(tt.body, tt.ref)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("got %s, want no time", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no time found, want %s", tt.want)
+			}
+			if want := utc(tt.want); !got.Equal(want) {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
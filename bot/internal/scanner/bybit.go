@@ -4,17 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"strings"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
 const (
-	bybitSource     = "bybit"
-	bybitListingURL = "https://api.bybit.com/v5/announcements/index?locale=en-US&limit=20&type=new_crypto"
+	bybitSource      = "bybit"
+	bybitBaseURL     = "https://api.bybit.com"
+	bybitListingPath = "/v5/announcements/index?locale=en-US&limit=20&type=new_crypto&page=%d"
 )
 
 type bybitResponse struct {
@@ -31,103 +31,100 @@ type bybitAnnouncement struct {
 }
 
 type BybitScanner struct {
-	client *http.Client
+	fetcher  *fetcher
+	bases    []string // основной хост и зеркала
+	maxPages int
+	window   config.Window
+
+	withDetails bool
+	pages       *fetcher
+	details     *detailCache
+	rules       *Classifier
 }
 
-func NewBybitScanner() *BybitScanner {
+func NewBybitScanner(opts Options) *BybitScanner {
 	return &BybitScanner{
-		client: &http.Client{Timeout: 15 * time.Second},
+		fetcher:  newFetcher(opts.client(), http.Header{"Accept": {"application/json"}}),
+		bases:    opts.baseURLs(bybitBaseURL),
+		maxPages: opts.MaxPages,
+		window:   opts.scanWindow(),
+
+		withDetails: opts.FetchDetails,
+		pages:       opts.pageFetcher(),
+		details:     newDetailCache(),
+		rules:       opts.classifier(),
 	}
 }
 
-func (s *BybitScanner) Scan(ctx context.Context) ([]model.Event, error) {
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	to := time.Now().UTC().Add(7 * 24 * time.Hour)
+func init() {
+	Register(bybitSource, func(opts Options) Scanner { return NewBybitScanner(opts) })
+}
+
+func (s *BybitScanner) Scan(ctx context.Context) (Result, error) {
+	from, to := s.window.Range(time.Now().UTC())
 
-	items, err := s.fetchAnnouncements(ctx)
+	items, served, err := walkPages(ctx, bybitSource, s.maxPages, from, s.fetchAnnouncements,
+		func(a bybitAnnouncement) time.Time { return time.UnixMilli(a.DateTimestamp) })
 	if err != nil {
-		log.Printf("[bybit] warning: %v", err)
-		return []model.Event{}, nil
+		return Result{}, fmt.Errorf("bybit: %w", err)
 	}
 
 	var events []model.Event
 	for _, a := range items {
-		ev, ok := s.parseAnnouncement(a, from, to)
-		if !ok {
-			continue
-		}
-		events = append(events, ev)
+		events = append(events, s.parseAnnouncement(a, from, to)...)
 	}
-	return deduplicateEvents(events), nil
-}
-
-func (s *BybitScanner) fetchAnnouncements(ctx context.Context) ([]bybitAnnouncement, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bybitListingURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
+	events = deduplicateEvents(events)
+	if s.withDetails {
+		events = refineEventTimes(ctx, bybitSource, events, s.details, fetchPageBody(s.pages))
 	}
-	req.Header.Set("Accept", "application/json")
+	return Result{Events: events, Fetched: len(items), Endpoint: served}, nil
+}
 
-	resp, err := s.client.Do(req)
+func (s *BybitScanner) fetchAnnouncements(ctx context.Context, page int) ([]bybitAnnouncement, string, error) {
+	path := fmt.Sprintf(bybitListingPath, page)
+	body, served, err := s.fetcher.fetch(ctx, endpoints(s.bases, path))
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d from bybit", resp.StatusCode)
+		return nil, "", err
 	}
 
 	var parsed bybitResponse
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", served, err)
 	}
-	return parsed.Result.List, nil
+	return parsed.Result.List, served, nil
 }
 
-func (s *BybitScanner) parseAnnouncement(a bybitAnnouncement, from, to time.Time) (model.Event, bool) {
+func (s *BybitScanner) parseAnnouncement(a bybitAnnouncement, from, to time.Time) []model.Event {
 	pubDate := time.Unix(a.DateTimestamp/1000, 0).UTC()
 
-	eventDate, _ := extractEventDateFromTitle(a.Title, pubDate)
+	eventDate, confidence := extractEventDateFromTitle(a.Title, pubDate)
 
 	if eventDate.Before(from) || eventDate.After(to) {
-		return model.Event{}, false
+		return nil
 	}
 
-	eventType, ok := classifyBybitTitle(a.Title)
+	class, ok := s.rules.Classify(bybitSource, a.Title)
 	if !ok {
-		return model.Event{}, false
+		return nil
 	}
 
-	token := extractTokenFromParentheses(a.Title)
-	if token == "" {
-		token = extractTokenFromTitle(a.Title)
-	}
-	if token == "" {
-		token = "UNKNOWN"
+	// Служебные анонсы (фиатные пары, региональная поддержка) — не листинги
+	if classifyNotice(a.Title) != "" {
+		return nil
 	}
 
-	return model.Event{
-		ID:      makeEventID(bybitSource, token, eventDate),
-		Type:    eventType,
+	return expandTokens(model.Event{
+		Type:    class.Type,
+		Subtype: class.Subtype,
 		Source:  bybitSource,
-		Token:   strings.ToUpper(token),
 		Title:   a.Title,
-		Date:    eventDate,
+		EventAt: eventDate,
 		URL:     a.URL,
 		Details: truncate(a.Description, 200),
-	}, true
-}
 
-func classifyBybitTitle(title string) (model.EventType, bool) {
-	upper := strings.ToUpper(title)
-	switch {
-	case strings.Contains(upper, "LAUNCHPOOL"):
-		return model.EventLaunchpool, true
-	case strings.Contains(upper, "LIST") || strings.Contains(upper, "PERPETUAL") ||
-		strings.Contains(upper, "FUTURES") || strings.Contains(upper, "CONVERT"):
-		return model.EventListing, true
-	default:
-		return "", false
-	}
+		AnnouncedAt:    pubDate,
+		DateConfidence: confidence,
+		GroupID:        articleGroup(bybitSource, articleRef(a.URL)),
+		Rule:           class.Rule,
+	}, extractTokensFromTitle(a.Title))
 }
@@ -0,0 +1,194 @@
+package scanner
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"crypto-bot/internal/model"
+)
+
+// defaultRules — встроенные правила классификации; формат описан в самом файле
+//
+//go:embed rules.yaml
+var defaultRules []byte
+
+// Rule — одно правило классификации заголовка анонса.
+type Rule struct {
+	Name    string   `yaml:"name"`
+	Sources []string `yaml:"sources"` // биржи, к которым применяется; пусто — все
+	Include []string `yaml:"include"` // подстроки, достаточно одной (без учёта регистра)
+	Regex   []string `yaml:"regex"`   // регулярки, достаточно одной (без учёта регистра)
+	Exclude []string `yaml:"exclude"` // подстроки, при которых правило не срабатывает
+
+	Skip    bool               `yaml:"skip"` // отбросить анонс
+	Type    model.EventType    `yaml:"type"`
+	Subtype model.EventSubtype `yaml:"subtype"`
+
+	// Examples — заголовки, которые должны попадать именно в это правило
+	Examples []string `yaml:"examples"`
+	// Negatives — похожие заголовки, которые в это правило попадать не должны
+	Negatives []string `yaml:"negatives"`
+
+	re []*regexp.Regexp
+}
+
+// Classification — итог классификации заголовка
+type Classification struct {
+	Type    model.EventType
+	Subtype model.EventSubtype
+	Rule    string // имя сработавшего правила
+}
+
+// Classifier — упорядоченный список правил, общий для всех бирж.
+type Classifier struct {
+	rules []Rule
+}
+
+var (
+	defaultClassifierOnce sync.Once
+	defaultClassifier     *Classifier
+)
+
+// DefaultClassifier возвращает классификатор со встроенными правилами.
+func DefaultClassifier() *Classifier {
+	defaultClassifierOnce.Do(func() {
+		c, err := ParseRules(defaultRules)
+		if err != nil {
+			panic("scanner: embedded rules.yaml: " + err.Error())
+		}
+		defaultClassifier = c
+	})
+	return defaultClassifier
+}
+
+// LoadClassifier читает правила из файла path; пустой path — встроенные правила.
+func LoadClassifier(path string) (*Classifier, error) {
+	if path == "" {
+		return DefaultClassifier(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+	c, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ParseRules разбирает YAML с правилами, компилирует регулярки и проверяет,
+// что каждый пример из examples классифицируется своим правилом, а из
+// negatives — нет.
+func ParseRules(data []byte) (*Classifier, error) {
+	var file struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse rules: %w", err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("no rules")
+	}
+
+	seen := make(map[string]bool)
+	for i := range file.Rules {
+		r := &file.Rules[i]
+		switch {
+		case r.Name == "":
+			return nil, fmt.Errorf("rule #%d: name is required", i+1)
+		case seen[r.Name]:
+			return nil, fmt.Errorf("rule %q: duplicate name", r.Name)
+		case r.Skip && r.Type != "":
+			return nil, fmt.Errorf("rule %q: skip and type are mutually exclusive", r.Name)
+		case !r.Skip && !r.Type.Valid():
+			return nil, fmt.Errorf("rule %q: unknown type %q", r.Name, r.Type)
+		case !r.Subtype.Valid():
+			return nil, fmt.Errorf("rule %q: unknown subtype %q", r.Name, r.Subtype)
+		}
+		seen[r.Name] = true
+		for _, expr := range r.Regex {
+			re, err := regexp.Compile("(?i)" + expr)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+			r.re = append(r.re, re)
+		}
+	}
+
+	c := &Classifier{rules: file.Rules}
+	for _, r := range c.rules {
+		source := ""
+		if len(r.Sources) > 0 {
+			source = r.Sources[0]
+		}
+		for _, ex := range r.Examples {
+			got := "no rule"
+			if m := c.match(source, ex); m != nil {
+				got = "rule " + m.Name
+			}
+			if got != "rule "+r.Name {
+				return nil, fmt.Errorf("rule %q: example %q matched %s", r.Name, ex, got)
+			}
+		}
+		for _, ex := range r.Negatives {
+			if m := c.match(source, ex); m != nil && m.Name == r.Name {
+				return nil, fmt.Errorf("rule %q: negative example %q matched it", r.Name, ex)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Classify определяет тип события по заголовку анонса биржи source.
+// false — ни одно правило не подошло либо сработало правило со skip.
+func (c *Classifier) Classify(source, title string) (Classification, bool) {
+	r := c.match(source, title)
+	if r == nil || r.Skip {
+		return Classification{}, false
+	}
+	return Classification{Type: r.Type, Subtype: r.Subtype, Rule: r.Name}, true
+}
+
+// match возвращает первое подходящее правило или nil
+func (c *Classifier) match(source, title string) *Rule {
+	upper := strings.ToUpper(title)
+	for i := range c.rules {
+		if c.rules[i].matches(source, upper) {
+			return &c.rules[i]
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(source, upper string) bool {
+	if len(r.Sources) > 0 && !slices.Contains(r.Sources, source) {
+		return false
+	}
+	for _, s := range r.Exclude {
+		if strings.Contains(upper, strings.ToUpper(s)) {
+			return false
+		}
+	}
+	if len(r.Include) == 0 && len(r.re) == 0 {
+		return true
+	}
+	for _, s := range r.Include {
+		if strings.Contains(upper, strings.ToUpper(s)) {
+			return true
+		}
+	}
+	for _, re := range r.re {
+		if re.MatchString(upper) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import "testing"
+
+// Корпус реальных заголовков анонсов: для каждого правила rules.yaml —
+// заголовки, которые должны в него попасть, и похожие, которые не должны.
+// rule "" — ни одно правило не подходит.
+var classifyCorpus = []struct {
+	source string
+	title  string
+	rule   string
+}{
+	// delisting
+	{"binance", "Binance Will Delist ALPACA, PDA, VIB, WING on 2025-05-02", "delisting"},
+	{"binance", "Notice of Removal of Spot Trading Pairs - 2026-02-20", "delisting"},
+	{"binance", "Binance Margin Will Delist KDA/BTC Isolated Margin Pair (2026-03-06)", "delisting"},
+	{"bybit", "Delisting of BAL/USDT Spot Trading Pair", "delisting"},
+	{"okx", "OKX to delist several spot trading pairs", "delisting"},
+	{"bybit", "Bybit Will Cease Trading on the ORBS/USDT and SC/USDT Spot Trading Pairs", "delisting"},
+	{"binance", "Binance Will List Kaito (KAITO) with Seed Tag Applied", "spot"},
+	// приостановка депозитов и выводов на время обновления сети — не делистинг
+	{"binance", "Binance Will Suspend Deposits and Withdrawals for Solana Network Upgrade", ""},
+	{"binance", "Binance Will Suspend Deposits and Withdrawals on the Arbitrum One Network", ""},
+	{"okx", "OKX to suspend deposits and withdrawals of ETH on Base during network upgrade", ""},
+
+	// notice — служебные анонсы пропускаются
+	{"binance", "Notice on New Trading Pairs & Trading Bots Services on Binance Spot", "notice"},
+	{"binance", "Notice on New Trading Pairs & Trading Bots Services on Binance Spot - 2026-02-27", "notice"},
+
+	// megadrop
+	{"binance", "Introducing Lista (LISTA) on Binance Megadrop! Farm LISTA by Locking BNB", "megadrop"},
+	{"binance", "Binance Megadrop: Web3 Quests for Solv Protocol (SOLV) Are Now Live", "megadrop"},
+
+	// hodler-airdrop: "Will List" в хвосте заголовка не делает его спотом
+	{"binance", "Introducing Kite (KITE) on Binance HODLer Airdrops! Binance Will List KITE with Seed Tag Applied", "hodler-airdrop"},
+	{"binance", "Introducing Plasma (XPL) on Binance HODLer Airdrops!", "hodler-airdrop"},
+	{"binance", "Binance Will List Plasma (XPL) with Seed Tag Applied", "spot"},
+
+	// launchpool
+	{"binance", "Introducing Solayer (LAYER) on Binance Launchpool! Farm LAYER by Staking BNB and FDUSD", "launchpool"},
+	{"bybit", "Bybit Launchpool: Stake MNT and USDT to Earn WAL", "launchpool"},
+	{"okx", "OKX Jumpstart to launch NOT mining", "launchpool"},
+	{"binance", "Binance Will List Solayer (LAYER) with Seed Tag Applied", "spot"},
+
+	// premarket — раньше фьючерсов: "perpetual futures for pre-market trading"
+	{"bybit", "Bybit Pre-Market: Trade ZKJ Before It Lists on Spot", "premarket"},
+	{"okx", "OKX to list perpetual futures for pre-market trading of Plasma (XPL)", "premarket"},
+	{"binance", "Binance Pre-Market Will List Usual (USUAL)", "premarket"},
+	{"okx", "OKX to list XPL USDT-margined perpetual futures", "perpetual"},
+
+	// perpetual
+	{"binance", "Binance Futures Will Launch USDⓈ-Margined BIRBUSDT Perpetual Contract With Up to 50x Leverage", "perpetual"},
+	{"bybit", "New Listing: AZTECUSDT Perpetual Contract", "perpetual"},
+	{"okx", "OKX to list BIRB USDT-margined perpetual futures", "perpetual"},
+	{"bybit", "New Listing: AZTEC/USDT", "spot"},
+
+	// spot
+	{"binance", "Binance Will List Pudgy Penguins (PENGU) with Seed Tag Applied", "spot"},
+	{"bybit", "New Listing: BIRB/USDT", "spot"},
+	{"bybit", "Bybit Lists OPN (OPN) on Spot", "spot"},
+	{"okx", "OKX to list ZKJ (ZKJ) for spot trading", "spot"},
+	{"okx", "OKX to support 60 tokens on unified USD order book", "spot"}, // дальше отсекает classifyNotice
+	{"binance", "Binance Completed the Network Integration of Sui (SUI)", ""},
+	{"binance", "Binance Earn: Enjoy Up to 20% APR on Solana (SOL) Staking", ""},
+	{"binance", "Binance Will Support the Solana (SOL) Network Upgrade", ""},
+
+	// bybit-convert — только для Bybit
+	{"bybit", "Bybit Convert Now Supports WAL (WAL)", "bybit-convert"},
+	{"okx", "OKX Convert Now Supports WAL (WAL)", ""},
+}
+
+func TestClassifyCorpus(t *testing.T) {
+	c := DefaultClassifier()
+	for _, tt := range classifyCorpus {
+		got := ""
+		if r := c.match(tt.source, tt.title); r != nil {
+			got = r.Name
+		}
+		if got != tt.rule {
+			t.Errorf("%s %q: rule %q, want %q", tt.source, tt.title, got, tt.rule)
+		}
+	}
+}
+
+func TestClassifyCorpusCoversEveryRule(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, tt := range classifyCorpus {
+		covered[tt.rule] = true
+	}
+	for _, r := range DefaultClassifier().rules {
+		if !covered[r.Name] {
+			t.Errorf("rule %q has no titles in the corpus", r.Name)
+		}
+	}
+}
+
+func TestParseRulesChecksNegatives(t *testing.T) {
+	_, err := ParseRules([]byte(`
+rules:
+  - name: delisting
+    regex: ['\bSUSPEND']
+    type: delisting
+    negatives:
+      - "Binance Will Suspend Deposits and Withdrawals for Solana Network Upgrade"
+`))
+	if err == nil {
+		t.Fatal("rule matching its own negative example loaded without error")
+	}
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"crypto-bot/internal/httpx"
+)
+
+const (
+	fetchEndpointTimeout = 10 * time.Second
+	fetchRetries         = 2 // повторов на эндпоинт после первой попытки
+	fetchBackoff         = 500 * time.Millisecond
+	fetchMaxBody         = 4 << 20 // 4 MB cap
+)
+
+// fetcher выполняет GET с перебором эндпоинтов по порядку. На каждый эндпоинт
+// свой таймаут; 5xx и 429 повторяются с экспоненциальной паузой, прочие ошибки
+// сразу переключают на следующий эндпоинт.
+type fetcher struct {
+	client  *http.Client
+	header  http.Header
+	timeout time.Duration // на одну попытку
+	retries int
+	backoff time.Duration
+}
+
+func newFetcher(client *http.Client, header http.Header) *fetcher {
+	return &fetcher{
+		client:  client,
+		header:  header,
+		timeout: fetchEndpointTimeout,
+		retries: fetchRetries,
+		backoff: fetchBackoff,
+	}
+}
+
+// statusError — неуспешный HTTP-статус от эндпоинта
+type statusError struct {
+	url  string
+	code int
+	wait time.Duration // из Retry-After, если прислали
+}
+
+func (e *statusError) Error() string { return fmt.Sprintf("status %d from %s", e.code, e.url) }
+
+func (e *statusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// fetch возвращает тело первого успешного ответа и URL, который его отдал.
+// Если не ответил ни один эндпоинт, ошибки всех попыток объединяются.
+func (f *fetcher) fetch(ctx context.Context, urls []string) ([]byte, string, error) {
+	var errs []error
+	for _, url := range urls {
+		body, err := f.fetchWithRetry(ctx, url)
+		if err == nil {
+			return body, url, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+func (f *fetcher) fetchWithRetry(ctx context.Context, url string) ([]byte, error) {
+	wait := f.backoff
+	for attempt := 0; ; attempt++ {
+		body, err := f.fetchOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		var se *statusError
+		if !errors.As(err, &se) || !se.retryable() || attempt >= f.retries {
+			return nil, err
+		}
+		pause := wait
+		if se.wait > pause {
+			pause = se.wait
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pause):
+		}
+		wait *= 2
+	}
+}
+
+func (f *fetcher) fetchOnce(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for k, vs := range f.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{url: url, code: resp.StatusCode, wait: httpx.RetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, fetchMaxBody))
+	if err != nil {
+		return nil, fmt.Errorf("read body from %s: %w", url, err)
+	}
+	return body, nil
+}
+
+// endpoints склеивает path со всеми базовыми адресами источника по порядку.
+func endpoints(bases []string, path string) []string {
+	out := make([]string, 0, len(bases))
+	for _, b := range bases {
+		out = append(out, b+path)
+	}
+	return out
+}
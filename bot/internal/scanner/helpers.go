@@ -2,7 +2,10 @@ package scanner
 
 import (
 	"fmt"
+	"html"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,7 +24,7 @@ var monthNames = map[string]time.Month{
 	"february": time.February, "feb": time.February,
 	"march": time.March, "mar": time.March,
 	"april": time.April, "apr": time.April,
-	"may": time.May,
+	"may":  time.May,
 	"june": time.June, "jun": time.June,
 	"july": time.July, "jul": time.July,
 	"august": time.August, "aug": time.August,
@@ -32,25 +35,29 @@ var monthNames = map[string]time.Month{
 }
 
 // extractEventDateFromTitle tries to extract the actual event date from the title text.
-// If nothing is found or the extracted date is more than 7 days before pubDate, it returns (fallback, false).
-func extractEventDateFromTitle(title string, fallback time.Time) (time.Time, bool) {
+// The confidence is DateExact when a time was found, DateOnly for a bare date.
+// If nothing is found or the extracted date is more than 7 days before pubDate,
+// it returns (fallback, DateInferred).
+func extractEventDateFromTitle(title string, fallback time.Time) (time.Time, model.DateConfidence) {
 	// Pattern 1: ISO date (2026-02-21) optionally with time 10:00 [UTC]
 	if m := reISODate.FindStringSubmatch(title); len(m) >= 2 {
 		dateStr := m[1]
 		t, err := time.Parse("2006-01-02", dateStr)
 		if err == nil {
+			confidence := model.DateOnly
 			if len(m) >= 3 && m[2] != "" {
 				parts := strings.Split(m[2], ":")
 				if len(parts) == 2 {
 					h, _ := strconv.Atoi(parts[0])
 					min, _ := strconv.Atoi(parts[1])
 					t = time.Date(t.Year(), t.Month(), t.Day(), h, min, 0, 0, time.UTC)
+					confidence = model.DateExact
 				}
 			} else {
 				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 			}
 			if !t.Before(fallback.Add(-7 * 24 * time.Hour)) {
-				return t, true
+				return t, confidence
 			}
 		}
 	}
@@ -65,16 +72,16 @@ func extractEventDateFromTitle(title string, fallback time.Time) (time.Time, boo
 				year := fallback.Year()
 				t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 				if !t.Before(fallback.Add(-7 * 24 * time.Hour)) {
-					return t, true
+					return t, model.DateOnly
 				}
 			}
 		}
 	}
 
-	return fallback, false
+	return fallback, model.DateInferred
 }
 
-// reParentheses matches the first parenthesised token ticker in an announcement
+// reParentheses matches a parenthesised token ticker in an announcement
 // e.g. "Bybit Will List BIRB (BIRB)" → "BIRB". Excludes years like (2026-02-21).
 var reParentheses = regexp.MustCompile(`\(([A-Z][A-Z0-9]{1,9})\)`)
 
@@ -105,7 +112,8 @@ var stopWords = map[string]bool{
 }
 
 // makeEventID produces a deterministic, collision-resistant event identifier.
-// Format: "source:TOKEN:YYYYMMDD"
+// Format: "source:TOKEN:YYYYMMDD". Used for events without an upstream
+// article (unlock schedules) and as the legacy ID of announcement events.
 func makeEventID(source, token string, date time.Time) string {
 	return fmt.Sprintf("%s:%s:%s",
 		source,
@@ -114,49 +122,173 @@ func makeEventID(source, token string, date time.Time) string {
 	)
 }
 
-// extractTokenFromParentheses returns the first ALL-CAPS ticker found inside
-// parentheses, e.g. "(BIRB)" → "BIRB". Returns "" when not found.
-func extractTokenFromParentheses(title string) string {
-	m := reParentheses.FindStringSubmatch(title)
-	if len(m) < 2 {
-		return ""
-	}
-	return m[1]
+// LegacyEventID returns the date-based ID an announcement event had before
+// IDs were derived from upstream articles — used to migrate cached entries.
+func LegacyEventID(e model.Event) string {
+	return makeEventID(e.Source, e.Token, e.EventAt)
 }
 
-// extractTokenFromTitle пытается извлечь тикер токена из заголовка анонса.
-// Порядок: (TICKER) → XYZUSDT пара → XYZBTC пара → первое незапрещённое слово
-func extractTokenFromTitle(title string) string {
-	// 1. Ищем (TICKER) — самый надёжный способ
-	if tok := extractTokenFromParentheses(title); tok != "" {
-		return tok
+// articleGroup builds the GroupID of an announcement: "source:ref", where ref
+// is the upstream article identifier (Binance article code, URL path).
+func articleGroup(source, ref string) string {
+	return source + ":" + ref
+}
+
+// makeArticleID produces an event ID from the announcement it came from:
+// "source:ref:TOKEN". It does not depend on the event date, so a rescheduled
+// listing keeps its identity (and its sent flags).
+func makeArticleID(group, token string) string {
+	return group + ":" + strings.ToUpper(token)
+}
+
+// articleRef reduces an announcement URL to a stable identifier: path
+// without scheme, host, query and surrounding slashes.
+// "https://www.okx.com/help/okx-to-list-abc?x=1" → "help/okx-to-list-abc"
+func articleRef(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Path == "" {
+		return strings.TrimSpace(rawURL)
 	}
+	return strings.Trim(u.Path, "/")
+}
+
+// fiatCodes — фиатные валюты: в заголовках это пары/рынки, а не листинг токена
+var fiatCodes = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "TRY": true, "BRL": true,
+	"AUD": true, "JPY": true, "RUB": true, "UAH": true, "ARS": true,
+	"MXN": true, "PLN": true, "ZAR": true, "AED": true, "NGN": true,
+	"KZT": true, "IDR": true, "VND": true, "INR": true, "CHF": true,
+	"CAD": true, "HKD": true, "SGD": true, "KRW": true, "CZK": true,
+	"RON": true, "COP": true, "PHP": true, "THB": true,
+}
+
+// isTicker сообщает, похоже ли слово на тикер токена
+func isTicker(w string) bool {
+	return !stopWords[w] && !fiatCodes[w] && len(w) >= 2 && len(w) <= 10
+}
 
-	// 2. Ищем пару вида XYZUSDT → XYZ
-	if m := reUSDTPair.FindStringSubmatch(title); len(m) >= 2 {
-		if !stopWords[m[1]] {
-			return m[1]
+// extractTokensFromTitle возвращает все тикеры из заголовка анонса без повторов,
+// в порядке появления: "Binance Will List A (A), B (B) and C (C)" → [A B C].
+// Порядок поиска: (TICKER) → XYZUSDT пары → XYZBTC пары → первое незапрещённое слово.
+// Более надёжный способ, если сработал, исключает менее надёжные.
+func extractTokensFromTitle(title string) []string {
+	collect := func(matches [][]string) []string {
+		var out []string
+		seen := make(map[string]bool)
+		for _, m := range matches {
+			if len(m) < 2 || !isTicker(m[1]) || seen[m[1]] {
+				continue
+			}
+			seen[m[1]] = true
+			out = append(out, m[1])
 		}
+		return out
 	}
 
-	// 3. Ищем пару вида XYZBTC → XYZ
-	if m := reBTCPair.FindStringSubmatch(title); len(m) >= 2 {
-		if !stopWords[m[1]] {
-			return m[1]
+	// 1. (TICKER) — самый надёжный способ
+	if toks := collect(reParentheses.FindAllStringSubmatch(title, -1)); len(toks) > 0 {
+		return toks
+	}
+	// 2. Пары вида XYZUSDT → XYZ
+	if toks := collect(reUSDTPair.FindAllStringSubmatch(title, -1)); len(toks) > 0 {
+		return toks
+	}
+	// 3. Пары вида XYZBTC → XYZ
+	if toks := collect(reBTCPair.FindAllStringSubmatch(title, -1)); len(toks) > 0 {
+		return toks
+	}
+	// 4. Fallback: первое ALL-CAPS слово не из стоп-листа — одно, дальше обычно шум
+	for _, m := range reUpperWord.FindAllString(title, -1) {
+		if isTicker(m) {
+			return []string{m}
 		}
 	}
+	return nil
+}
 
-	// 4. Fallback: первое ALL-CAPS слово не из стоп-листа
-	matches := reUpperWord.FindAllString(title, -1)
-	for _, m := range matches {
-		if !stopWords[m] && len(m) >= 2 && len(m) <= 10 {
-			return m
-		}
+// extractTokenFromTitle возвращает первый тикер из заголовка или "".
+// Для источников, где одна запись — всегда один токен (аирдропы).
+func extractTokenFromTitle(title string) string {
+	if toks := extractTokensFromTitle(title); len(toks) > 0 {
+		return toks[0]
 	}
+	return ""
+}
 
+// Виды служебных анонсов, которые похожи на листинг, но им не являются
+const (
+	noticeFiatPair = "fiat-pair"      // новые фиатные пары/рынки для уже торгуемых токенов
+	noticeRegion   = "region-support" // поддержка токенов в регионе / на региональной площадке
+)
+
+var (
+	// "BTC/EUR", "USD trading pairs", "USD and EUR spot trading pairs", "USD/EUR markets"
+	reFiatPair = regexp.MustCompile(`\b[A-Z0-9]{2,10}/(` + fiatAlternation() + `)\b|` +
+		`\b(` + fiatAlternation() + `)((, |,? AND | & |/)(` + fiatAlternation() + `))* (SPOT )?(TRADING )?(PAIRS?|MARKETS?|ORDER BOOKS?)\b`)
+	// reRegion — региональные обороты целыми словами: EEA в любом обороте
+	// ("in EEA", "EEA users"), "in selected regions", "local currencies",
+	// "fiat markets", "unified order book". Голые REGION и FIAT не ищем —
+	// так называются и токены.
+	reRegion = regexp.MustCompile(`\bEEA\b|` +
+		`\b(IN|FOR) (THE )?(SELECTED |SUPPORTED |CERTAIN |SPECIFIC )?REGIONS?\b|` +
+		`\bLOCAL CURRENC(Y|IES)\b|` +
+		`\bFIAT (CURRENC(Y|IES)|PAIRS?|MARKETS?|TRADING|DEPOSITS?|GATEWAY|ON-RAMP)\b|` +
+		`\bUNIFIED ([A-Z]+ )?ORDER BOOKS?\b`)
+	// reUsersIn — "for Users in Brazil": регион — слово после оборота
+	reUsersIn = regexp.MustCompile(`\bUSERS (IN|FROM) (THE )?([A-Z]+)\b`)
+	// notRegions — слова после "users in", которые регион не называют
+	// ("available to users in all regions")
+	notRegions = map[string]bool{"ALL": true, "ANY": true, "EVERY": true, "MOST": true, "OUR": true}
+)
+
+// fiatAlternation собирает "USD|EUR|..." для регулярных выражений
+func fiatAlternation() string {
+	codes := make([]string, 0, len(fiatCodes))
+	for c := range fiatCodes {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, "|")
+}
+
+// classifyNotice распознаёт служебные анонсы — фиатные пары и региональную
+// поддержку ("OKX to support 60 tokens on unified USD order book"). Возвращает
+// вид анонса или "", если это обычный анонс.
+func classifyNotice(title string) string {
+	upper := strings.ToUpper(title)
+	if reFiatPair.MatchString(upper) {
+		return noticeFiatPair
+	}
+	if reRegion.MatchString(upper) {
+		return noticeRegion
+	}
+	if m := reUsersIn.FindStringSubmatch(upper); m != nil && !notRegions[m[3]] {
+		return noticeRegion
+	}
 	return ""
 }
 
+// expandTokens размножает событие-шаблон на каждый тикер: у всех копий общий
+// GroupID анонса, а ID и Token — свои. ID строится из GroupID, без него — из
+// даты. Без тикеров — одно событие "UNKNOWN".
+func expandTokens(tmpl model.Event, tokens []string) []model.Event {
+	if len(tokens) == 0 {
+		tokens = []string{model.UnknownToken}
+	}
+	out := make([]model.Event, 0, len(tokens))
+	for _, tok := range tokens {
+		e := tmpl
+		e.Token = strings.ToUpper(tok)
+		if e.GroupID != "" {
+			e.ID = makeArticleID(e.GroupID, e.Token)
+		} else {
+			e.ID = makeEventID(e.Source, e.Token, e.EventAt)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
 // deduplicateEvents removes events with duplicate IDs, keeping the first
 // occurrence. This is used when multiple API endpoints can return the same
 // event (e.g. Binance listing + launchpool feeds both mention the same token).
@@ -181,3 +313,22 @@ func truncate(s string, maxRunes int) string {
 	}
 	return string(runes[:maxRunes-1]) + "…"
 }
+
+// stripHTML removes tags, decodes entities and collapses whitespace.
+// Simple tag removal — adequate for RSS descriptions and announcement pages.
+func stripHTML(s string) string {
+	var out strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+			out.WriteRune(' ')
+		case r == '>':
+			inTag = false
+		case !inTag:
+			out.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(out.String())), " ")
+}
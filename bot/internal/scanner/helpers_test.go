@@ -0,0 +1,59 @@
+package scanner
+
+import "testing"
+
+func TestClassifyNotice(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		// несколько фиатных валют перед парами
+		{"OKX to support 60 tokens on unified USD and EUR spot trading pairs in EEA", noticeFiatPair},
+		{"Binance Will Add New TRY, EUR & BRL Trading Pairs", noticeFiatPair},
+		{"Bybit Lists USDC/EUR and USDT/EUR Trading Pairs on Spot", noticeFiatPair},
+		{"OKX to support 60 tokens on unified USD order book", noticeFiatPair},
+		{"Binance Will Open USD/EUR Markets for Spot Trading", noticeFiatPair},
+		// EEA — отдельным словом в любом обороте
+		{"OKX to list ZKJ for spot trading in EEA", noticeRegion},
+		{"Bybit EU: New Listings for the EEA", noticeRegion},
+		{"Spot trading for SOL now available to EEA users", noticeRegion},
+		{"Binance Will Support New Tokens for Users in Brazil", noticeRegion},
+		{"Bybit Lists SOL for Users in the Netherlands", noticeRegion},
+		{"OKX Adds Spot Trading in Selected Regions", noticeRegion},
+		{"Binance Will Support Local Currencies on Convert", noticeRegion},
+		{"Bybit Opens Fiat Markets for BTC and ETH", noticeRegion},
+		{"OKX to support 60 tokens on unified order book", noticeRegion},
+		// обычные листинги
+		{"Binance Will List Pudgy Penguins (PENGU) with Seed Tag Applied", ""},
+		{"OKX to list Seeax (SEEAX) for spot trading", ""},
+		{"New Listing: AZTECUSDT Perpetual Contract", ""},
+		{"Binance Futures Will Launch USDⓈ-Margined BIRBUSDT Perpetual Contract With Up to 50x Leverage", ""},
+		{"Bybit Lists OPN (OPN) on Spot", ""},
+		// FIAT и REGION в названии или тикере, "all regions" — тоже листинги
+		{"Binance Will List FiatCoin (FIAT) with Seed Tag Applied", ""},
+		{"OKX to list Region Network (REGION) for spot trading", ""},
+		{"Bybit Lists REGIONUSDT Perpetual Contract", ""},
+		{"KITE Spot Trading Now Available to Users in All Regions", ""},
+		{"Binance Will List Kite (KITE) for Users in Any Supported Region", ""},
+		{"New Listing: ORDERUSDT Perpetual Contract with Order Book Depth Boost", ""},
+	}
+	for _, tt := range tests {
+		if got := classifyNotice(tt.title); got != tt.want {
+			t.Errorf("classifyNotice(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestParseDetailSkipsRegionalNotices(t *testing.T) {
+	s := NewOKXScanner(Options{})
+	from, to := testWindow()
+	for _, title := range []string{
+		"OKX to support 60 tokens on unified USD and EUR spot trading pairs in EEA",
+		"OKX to list ZKJ for spot trading in EEA",
+	} {
+		d := okxDetail{Title: title, URL: "https://www.okx.com/help/notice", PTime: "1772409600000"}
+		if events := s.parseDetail(d, from, to); len(events) != 0 {
+			t.Errorf("%q: got %d events, want none (%s/%s)", title, len(events), events[0].Type, events[0].Subtype)
+		}
+	}
+}
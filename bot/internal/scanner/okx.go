@@ -4,18 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"strings"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
 const (
 	okxSource = "okx"
 	// Официальный API OKX анонсов: тип "announcements-new-listings"
-	okxURL = "https://www.okx.com/api/v5/support/announcements?page=1&limit=20&annType=announcements-new-listings"
+	okxBaseURL     = "https://www.okx.com"
+	okxListingPath = "/api/v5/support/announcements?page=%d&limit=20&annType=announcements-new-listings"
 )
 
 // okxResponse — обёртка ответа OKX API
@@ -27,68 +27,78 @@ type okxResponse struct {
 }
 
 type okxDetail struct {
-	AnnType      string `json:"annType"`
-	Title        string `json:"title"`
-	URL          string `json:"url"`
-	PTime        string `json:"pTime"`        // ms UTC как строка
+	AnnType       string `json:"annType"`
+	Title         string `json:"title"`
+	URL           string `json:"url"`
+	PTime         string `json:"pTime"`         // ms UTC как строка
 	BusinessPTime string `json:"businessPTime"` // ms UTC как строка
 }
 
 type OKXScanner struct {
-	client *http.Client
+	fetcher  *fetcher
+	bases    []string // основной хост и зеркала
+	maxPages int
+	window   config.Window
+
+	withDetails bool
+	pages       *fetcher
+	details     *detailCache
+	rules       *Classifier
 }
 
-func NewOKXScanner() *OKXScanner {
+func NewOKXScanner(opts Options) *OKXScanner {
 	return &OKXScanner{
-		client: &http.Client{Timeout: 15 * time.Second},
+		fetcher: newFetcher(opts.client(), http.Header{
+			"Accept":     {"application/json"},
+			"User-Agent": {binanceUserAgent},
+		}),
+		bases:    opts.baseURLs(okxBaseURL),
+		maxPages: opts.MaxPages,
+		window:   opts.scanWindow(),
+
+		withDetails: opts.FetchDetails,
+		pages:       opts.pageFetcher(),
+		details:     newDetailCache(),
+		rules:       opts.classifier(),
 	}
 }
 
-func (s *OKXScanner) Scan(ctx context.Context) ([]model.Event, error) {
-	from := time.Now().UTC().Add(-14 * 24 * time.Hour)
-	to := time.Now().UTC().Add(7 * 24 * time.Hour)
+func init() {
+	Register(okxSource, func(opts Options) Scanner { return NewOKXScanner(opts) })
+}
+
+func (s *OKXScanner) Scan(ctx context.Context) (Result, error) {
+	from, to := s.window.Range(time.Now().UTC())
 
-	details, err := s.fetchDetails(ctx)
+	details, served, err := walkPages(ctx, okxSource, s.maxPages, from, s.fetchDetails, okxPublished)
 	if err != nil {
-		log.Printf("[okx] warning: %v", err)
-		return []model.Event{}, nil
+		return Result{}, fmt.Errorf("okx: %w", err)
 	}
 
 	var events []model.Event
 	for _, d := range details {
-		ev, ok := s.parseDetail(d, from, to)
-		if !ok {
-			continue
-		}
-		events = append(events, ev)
+		events = append(events, s.parseDetail(d, from, to)...)
 	}
-	return events, nil
-}
-
-func (s *OKXScanner) fetchDetails(ctx context.Context) ([]okxDetail, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, okxURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
+	events = deduplicateEvents(events)
+	if s.withDetails {
+		events = refineEventTimes(ctx, okxSource, events, s.details, fetchPageBody(s.pages))
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", binanceUserAgent)
+	return Result{Events: events, Fetched: len(details), Endpoint: served}, nil
+}
 
-	resp, err := s.client.Do(req)
+func (s *OKXScanner) fetchDetails(ctx context.Context, page int) ([]okxDetail, string, error) {
+	path := fmt.Sprintf(okxListingPath, page)
+	body, served, err := s.fetcher.fetch(ctx, endpoints(s.bases, path))
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d from okx", resp.StatusCode)
+		return nil, "", err
 	}
 
 	var parsed okxResponse
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return nil, fmt.Errorf("decode: %w", err)
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", served, err)
 	}
 	if parsed.Code != "0" {
-		return nil, fmt.Errorf("okx api error code: %s", parsed.Code)
+		return nil, "", fmt.Errorf("okx api error code: %s", parsed.Code)
 	}
 
 	// Разворачиваем вложенный список
@@ -96,61 +106,57 @@ func (s *OKXScanner) fetchDetails(ctx context.Context) ([]okxDetail, error) {
 	for _, group := range parsed.Data {
 		all = append(all, group.Details...)
 	}
-	return all, nil
+	return all, served, nil
 }
 
-func (s *OKXScanner) parseDetail(d okxDetail, from, to time.Time) (model.Event, bool) {
+// okxPublished возвращает дату публикации анонса (нулевую, если её нет)
+func okxPublished(d okxDetail) time.Time {
 	// pTime — миллисекунды UTC как строка
 	ms := parseMillisString(d.PTime)
 	if ms == 0 {
 		ms = parseMillisString(d.BusinessPTime)
 	}
 	if ms == 0 {
-		return model.Event{}, false
+		return time.Time{}
+	}
+	return time.Unix(ms/1000, 0).UTC()
+}
+
+func (s *OKXScanner) parseDetail(d okxDetail, from, to time.Time) []model.Event {
+	pubDate := okxPublished(d)
+	if pubDate.IsZero() {
+		return nil
 	}
-	pubDate := time.Unix(ms/1000, 0).UTC()
 
-	eventDate, _ := extractEventDateFromTitle(d.Title, pubDate)
+	eventDate, confidence := extractEventDateFromTitle(d.Title, pubDate)
 
 	if eventDate.Before(from) || eventDate.After(to) {
-		return model.Event{}, false
+		return nil
 	}
 
-	eventType, ok := classifyOKXTitle(d.Title)
+	class, ok := s.rules.Classify(okxSource, d.Title)
 	if !ok {
-		return model.Event{}, false
+		return nil
 	}
 
-	token := extractTokenFromParentheses(d.Title)
-	if token == "" {
-		token = extractTokenFromTitle(d.Title)
-	}
-	if token == "" {
-		token = "UNKNOWN"
+	// Служебные анонсы (фиатные пары, региональная поддержка) — не листинги
+	if classifyNotice(d.Title) != "" {
+		return nil
 	}
 
-	return model.Event{
-		ID:     makeEventID(okxSource, token, eventDate),
-		Type:   eventType,
-		Source: okxSource,
-		Token:  strings.ToUpper(token),
-		Title:  d.Title,
-		Date:   eventDate,
-		URL:    d.URL,
-	}, true
-}
+	return expandTokens(model.Event{
+		Type:    class.Type,
+		Subtype: class.Subtype,
+		Source:  okxSource,
+		Title:   d.Title,
+		EventAt: eventDate,
+		URL:     d.URL,
 
-func classifyOKXTitle(title string) (model.EventType, bool) {
-	upper := strings.ToUpper(title)
-	switch {
-	case strings.Contains(upper, "JUMPSTART"):
-		return model.EventLaunchpool, true
-	case strings.Contains(upper, "TO LIST") || strings.Contains(upper, "WILL LIST") ||
-		strings.Contains(upper, "LISTING") || strings.Contains(upper, "TO SUPPORT"):
-		return model.EventListing, true
-	default:
-		return "", false
-	}
+		AnnouncedAt:    pubDate,
+		DateConfidence: confidence,
+		GroupID:        articleGroup(okxSource, articleRef(d.URL)),
+		Rule:           class.Rule,
+	}, extractTokensFromTitle(d.Title))
 }
 
 // parseMillisString разбирает строку с Unix timestamp в миллисекундах
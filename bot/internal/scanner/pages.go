@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// defaultMaxPages — сколько страниц ленты анонсов листать максимум,
+// если в config.yaml не задан max_pages.
+const defaultMaxPages = 5
+
+// pageFunc загружает одну страницу ленты (нумерация с 1) и сообщает,
+// какой эндпоинт её отдал.
+type pageFunc[T any] func(ctx context.Context, page int) ([]T, string, error)
+
+// walkPages листает ленту анонсов с первой страницы, пока не встретит пустую
+// страницу, страницу целиком старше from (лента отсортирована от новых к старым)
+// или не упрётся в maxPages. Ошибка первой страницы возвращается — источник
+// недоступен; ошибка на более глубокой странице только логируется, а уже
+// собранные записи возвращаются.
+func walkPages[T any](ctx context.Context, source string, maxPages int, from time.Time,
+	fetchPage pageFunc[T], published func(T) time.Time) ([]T, string, error) {
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	var (
+		all    []T
+		served string
+	)
+	for page := 1; page <= maxPages; page++ {
+		items, endpoint, err := fetchPage(ctx, page)
+		if err != nil {
+			if page == 1 {
+				return nil, "", err
+			}
+			log.Printf("[%s] warning: page %d: %v", source, page, err)
+			break
+		}
+		served = endpoint
+		all = append(all, items...)
+		if len(items) == 0 || allBefore(items, from, published) {
+			break
+		}
+	}
+	return all, served, nil
+}
+
+// allBefore сообщает, что все записи страницы опубликованы раньше from.
+func allBefore[T any](items []T, from time.Time, published func(T) time.Time) bool {
+	for _, it := range items {
+		if !published(it).Before(from) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,296 @@
+package scanner
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/httpx"
+	"crypto-bot/internal/model"
+)
+
+// Парсеры источников на сохранённых ответах из testdata: сканер ходит в сеть
+// через httpx.ReplayTransport, а окно задаётся от фиксированного момента, чтобы
+// фикстуры не устаревали. Обновить фикстуры: go run ./cmd/scantest -record internal/scanner/testdata
+// (после перезаписи поправьте ожидания и testNow).
+
+// testNow — «текущий» момент для фикстур в testdata
+var testNow = time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+// testWindow — окно по умолчанию (14 дней назад, 7 вперёд) от testNow
+func testWindow() (from, to time.Time) {
+	return defaultWindow.Range(testNow)
+}
+
+func replayOptions() Options {
+	return Options{Transport: &httpx.ReplayTransport{Dir: "testdata"}}
+}
+
+// wantEvent — поля события, которые проверяют тесты парсеров
+type wantEvent struct {
+	ID         string
+	Type       model.EventType
+	Subtype    model.EventSubtype
+	Token      string
+	At         time.Time
+	Confidence model.DateConfidence
+}
+
+func checkEvents(t *testing.T, got []model.Event, want []wantEvent) {
+	t.Helper()
+	if len(got) != len(want) {
+		for _, e := range got {
+			t.Logf("got %s %s/%s %s %s", e.ID, e.Type, e.Subtype, e.Token, e.EventAt)
+		}
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		e := got[i]
+		g := wantEvent{e.ID, e.Type, e.Subtype, e.Token, e.EventAt, e.DateConfidence}
+		if !g.At.Equal(w.At) {
+			t.Errorf("event %d: EventAt = %s, want %s", i, g.At, w.At)
+		}
+		g.At = w.At
+		if g != w {
+			t.Errorf("event %d:\n got  %+v\n want %+v", i, g, w)
+		}
+	}
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseArticle(t *testing.T) {
+	s := NewBinanceScanner(replayOptions())
+	from, to := testWindow()
+
+	tests := []struct {
+		catalog int
+		want    []wantEvent
+	}{
+		{binanceListingCat, []wantEvent{
+			{"binance:1f0e3c4bd5a14e6f8a9b2c3d4e5f6a7b:BIRB", model.EventListing, model.SubtypeFuturesLaunch, "BIRB", utc("2026-03-02T07:30:00Z"), model.DateInferred},
+			{"binance:7d3f1c2a9b8e4f5a8c6d2e1f0a9b8c7d:PENGU", model.EventListing, model.SubtypeSpotListing, "PENGU", utc("2026-03-01T09:52:00Z"), model.DateInferred},
+			// дата в скобках в конце заголовка — дата события
+			{"binance:9a8b7c6d5e4f40312a1b0c9d8e7f6a5b:KDA", model.EventDelisting, "", "KDA", utc("2026-03-06T00:00:00Z"), model.DateOnly},
+			// Notice on ... — пропускается, BMT — вне окна
+		}},
+		{binanceLaunchCat, []wantEvent{
+			{"binance:5b6c7d8e9f0a41b2c3d4e5f60718293a:LAYER", model.EventLaunchpool, "", "LAYER", utc("2026-03-02T10:00:00Z"), model.DateInferred},
+			{"binance:3a4b5c6d7e8f49a0b1c2d3e4f5a6b7c8:KITE", model.EventHodlerAirdrop, "", "KITE", utc("2026-02-28T12:00:00Z"), model.DateInferred},
+		}},
+	}
+	for _, tt := range tests {
+		articles, _, err := s.fetchArticles(context.Background(), tt.catalog, 1)
+		if err != nil {
+			t.Fatalf("catalog %d: %v", tt.catalog, err)
+		}
+		var events []model.Event
+		for _, a := range articles {
+			events = append(events, s.parseArticle(a, from, to)...)
+		}
+		checkEvents(t, events, tt.want)
+	}
+}
+
+func TestParseAnnouncement(t *testing.T) {
+	s := NewBybitScanner(replayOptions())
+	from, to := testWindow()
+
+	items, _, err := s.fetchAnnouncements(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []model.Event
+	for _, a := range items {
+		events = append(events, s.parseAnnouncement(a, from, to)...)
+	}
+	checkEvents(t, events, []wantEvent{
+		// время открытия торгов в заголовке — точное время события
+		{"bybit:article/bybit-lists-opn-opn-on-spot-blt5f3a9c1e2d4b6a78:OPN", model.EventListing, model.SubtypeSpotListing, "OPN", utc("2026-03-04T10:00:00Z"), model.DateExact},
+		{"bybit:article/new-listing-aztecusdt-perpetual-contract-blt1a2b3c4d5e6f7a8b:AZTEC", model.EventListing, model.SubtypeFuturesLaunch, "AZTEC", utc("2026-03-01T08:00:00Z"), model.DateInferred},
+		{"bybit:article/bybit-convert-now-supports-wal-blt9e8d7c6b5a4f3e2d:WAL", model.EventListing, model.SubtypeSpotListing, "WAL", utc("2026-02-28T06:00:00Z"), model.DateInferred},
+		// USDC/EUR — фиатные пары, BIRB/USDT — вне окна
+	})
+	if got := events[0].Details; got != "Bybit is excited to announce the listing of OPN on our Spot trading platform." {
+		t.Errorf("Details = %q", got)
+	}
+}
+
+func TestParseDetail(t *testing.T) {
+	s := NewOKXScanner(replayOptions())
+	from, to := testWindow()
+
+	details, _, err := s.fetchDetails(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []model.Event
+	for _, d := range details {
+		events = append(events, s.parseDetail(d, from, to)...)
+	}
+	checkEvents(t, events, []wantEvent{
+		{"okx:help/okx-to-list-zkj-zkj-for-spot-trading:ZKJ", model.EventListing, model.SubtypeSpotListing, "ZKJ", utc("2026-03-02T04:00:00Z"), model.DateInferred},
+		{"okx:help/okx-to-list-birb-usdt-margined-perpetual-futures:BIRB", model.EventListing, model.SubtypeFuturesLaunch, "BIRB", utc("2026-03-01T08:00:00Z"), model.DateInferred},
+		// "on March 5" в заголовке
+		{"okx:help/okx-to-list-morpho-morpho-for-spot-trading:MORPHO", model.EventListing, model.SubtypeSpotListing, "MORPHO", utc("2026-03-05T00:00:00Z"), model.DateOnly},
+		// pTime пуст — дата из businessPTime
+		{"okx:help/okx-to-list-perpetual-futures-for-pre-market-trading-of-plasma-xpl:XPL", model.EventPremarket, "", "XPL", utc("2026-02-27T10:00:00Z"), model.DateInferred},
+		// USD order book — служебный анонс, GRASS — без даты публикации
+	})
+}
+
+func TestParseUnlock(t *testing.T) {
+	from := testNow
+	horizon := testNow.Add(45 * 24 * time.Hour)
+	s := NewUnlocksScanner(Options{
+		Transport: &httpx.ReplayTransport{Dir: "testdata"},
+		Window:    config.Window{Horizon: 45 * 24 * time.Hour},
+	})
+
+	unlocks, _, err := s.fetchUnlocks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		token   string
+		ok      bool
+		id      string
+		percent float64
+		usd     float64
+		alloc   model.UnlockAllocation
+	}{
+		{"arb", true, "tokenunlocks:ARB:20260316", 1.1, 48_500_000, model.UnlockAllocation{Category: model.UnlockTeam, Kind: model.VestingCliff, Percent: 1.1, ValueUSD: 48_500_000}},
+		{"ARB", true, "tokenunlocks:ARB:20260316", 0.8, 35_200_000, model.UnlockAllocation{Category: model.UnlockInvestors, Kind: model.VestingCliff, Percent: 0.8, ValueUSD: 35_200_000}},
+		{"SUI", false, "", 0, 0, model.UnlockAllocation{}}, // уже прошёл
+		{"APT", true, "tokenunlocks:APT:20260312", 1.9, 67_000_000, model.UnlockAllocation{Category: model.UnlockEcosystem, Kind: model.VestingLinear, Percent: 1.9, ValueUSD: 67_000_000}},
+		{"STRK", true, "tokenunlocks:STRK:20260415", 2.3, 21_000_000, model.UnlockAllocation{Category: model.UnlockTeam, Kind: model.VestingCliff, Percent: 2.3, ValueUSD: 21_000_000}},
+		{"", false, "", 0, 0, model.UnlockAllocation{}},    // без тикера и имени
+		{"ENA", false, "", 0, 0, model.UnlockAllocation{}}, // без даты
+		{"TIA", false, "", 0, 0, model.UnlockAllocation{}}, // дата не в ISO
+		// без тикера — различаются по имени
+		{"", true, "tokenunlocks:UNKNOWN:20260320:alpha-protocol", 3, 5_000_000, model.UnlockAllocation{Category: model.UnlockTeam, Kind: model.VestingCliff, Percent: 3, ValueUSD: 5_000_000}},
+		{"", true, "tokenunlocks:UNKNOWN:20260320:beta-network", 4, 6_000_000, model.UnlockAllocation{Category: model.UnlockInvestors, Kind: model.VestingCliff, Percent: 4, ValueUSD: 6_000_000}},
+	}
+	if len(unlocks) != len(tests) {
+		t.Fatalf("got %d unlocks in fixture, want %d", len(unlocks), len(tests))
+	}
+	for i, tt := range tests {
+		u := unlocks[i]
+		if u.Token != tt.token {
+			t.Fatalf("unlock %d: token %q, want %q", i, u.Token, tt.token)
+		}
+		ev, ok := parseUnlock(u, from, horizon)
+		if ok != tt.ok {
+			t.Errorf("%q: ok = %v, want %v", tt.token, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if ev.ID != tt.id || ev.Type != model.EventUnlock || ev.DateConfidence != model.DateOnly {
+			t.Errorf("%q: got %s %s %s", tt.token, ev.ID, ev.Type, ev.DateConfidence)
+		}
+		if tt.alloc == (model.UnlockAllocation{}) {
+			if ev.Unlock != nil {
+				t.Errorf("%q: Unlock = %+v, want nil", tt.token, ev.Unlock)
+			}
+			continue
+		}
+		if ev.Unlock == nil || ev.Unlock.Percent != tt.percent || ev.Unlock.ValueUSD != tt.usd ||
+			len(ev.Unlock.Allocations) != 1 || ev.Unlock.Allocations[0] != tt.alloc {
+			t.Errorf("%q: Unlock = %+v", tt.token, ev.Unlock)
+		}
+	}
+
+	// Два распределения ARB в один день сливаются в одно событие
+	ev, _ := parseUnlock(unlocks[0], from, horizon)
+	ev = addAllocation(ev, unlocks[1])
+	if math.Abs(ev.Unlock.Percent-1.9) > 1e-9 || ev.Unlock.ValueUSD != 83_700_000 || len(ev.Unlock.Allocations) != 2 {
+		t.Errorf("merged ARB unlock = %+v", ev.Unlock)
+	}
+	if want := "разлок 1.9% supply ~$84M (team, investors)"; ev.Details != want {
+		t.Errorf("merged ARB details = %q, want %q", ev.Details, want)
+	}
+	if ev.Title != "Arbitrum (ARB) — разлок токенов" {
+		t.Errorf("Title = %q", ev.Title)
+	}
+
+	// Разлоки без тикера разных проектов в один день не складываются
+	sizes := make(map[string]float64)
+	for _, e := range groupUnlocks(unlocks, from, horizon) {
+		sizes[e.ID] = e.Unlock.Percent
+	}
+	want := map[string]float64{
+		"tokenunlocks:ARB:20260316":                    1.9,
+		"tokenunlocks:APT:20260312":                    1.9,
+		"tokenunlocks:STRK:20260415":                   2.3,
+		"tokenunlocks:UNKNOWN:20260320:alpha-protocol": 3,
+		"tokenunlocks:UNKNOWN:20260320:beta-network":   4,
+	}
+	if len(sizes) != len(want) {
+		t.Errorf("grouped unlocks = %v, want %v", sizes, want)
+	}
+	for id, p := range want {
+		if math.Abs(sizes[id]-p) > 1e-9 {
+			t.Errorf("%s: percent %v, want %v", id, sizes[id], p)
+		}
+	}
+}
+
+func TestParseRSSItem(t *testing.T) {
+	s := NewAirdropsScanner(replayOptions())
+	from, to := testWindow()
+
+	items, _, err := s.fetchFeed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		title   string
+		ok      bool
+		id      string
+		token   string
+		at      time.Time
+		details string
+	}{
+		{"Monad (MON) Airdrop", true, "airdrops:monad:MON", "MON", utc("2026-03-02T12:14:05Z"),
+			"Monad is a high-performance EVM-compatible Layer 1 blockchain. Testnet users can claim free MON tokens & more."},
+		// день без ведущего нуля и пояс GMT
+		{"Linea Surge Airdrop", true, "airdrops:linea-surge:UNKNOWN", "UNKNOWN", utc("2026-03-01T18:30:00Z"),
+			"Provide liquidity on Linea to earn LXP-L points."},
+		{"Grass Season 2 Airdrop", false, "", "", time.Time{}, ""}, // вне окна
+		{"Broken Date Airdrop", false, "", "", time.Time{}, ""},    // pubDate не разбирается
+	}
+	if len(items) != len(tests) {
+		t.Fatalf("got %d items in fixture, want %d", len(items), len(tests))
+	}
+	for i, tt := range tests {
+		if items[i].Title != tt.title {
+			t.Fatalf("item %d: title %q, want %q", i, items[i].Title, tt.title)
+		}
+		ev, ok := parseRSSItem(items[i], from, to)
+		if ok != tt.ok {
+			t.Errorf("%q: ok = %v, want %v", tt.title, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if ev.ID != tt.id || ev.Token != tt.token || !ev.EventAt.Equal(tt.at) || ev.Details != tt.details {
+			t.Errorf("%q: got %s %s %s %q", tt.title, ev.ID, ev.Token, ev.EventAt, ev.Details)
+		}
+		if ev.Type != model.EventAirdrop || ev.DateConfidence != model.DateInferred {
+			t.Errorf("%q: got %s %s", tt.title, ev.Type, ev.DateConfidence)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bot/internal/config"
+	"crypto-bot/internal/model"
+)
+
+// Scanner — интерфейс для всех источников данных.
+// Ошибка означает, что источник недоступен; пустой Result без ошибки —
+// источник ответил, но событий нет.
+type Scanner interface {
+	Scan(ctx context.Context) (Result, error)
+}
+
+// Result — итог одного прохода сканера.
+type Result struct {
+	Events   []model.Event
+	Fetched  int    // сколько сырых записей вернул источник (до фильтрации)
+	Endpoint string // какой эндпоинт (основной или зеркало) отдал данные
+}
+
+// Factory строит сканер с заданными параметрами доступа к источнику.
+type Factory func(opts Options) Scanner
+
+// Options — параметры HTTP-доступа сканера. Нулевое значение — боевой
+// адрес источника и http.DefaultTransport.
+type Options struct {
+	BaseURL  string        // схема+хост API, напр. "https://api.bybit.com"; пусто — по умолчанию
+	Mirrors  []string      // запасные хосты с тем же API, перебираются после BaseURL
+	MaxPages int           // лимит страниц ленты анонсов; 0 — defaultMaxPages
+	Window   config.Window // окно сканирования; нулевое — defaultWindow
+	// FetchDetails — догружать страницы анонсов без точного времени
+	FetchDetails bool
+	Classifier   *Classifier       // правила классификации анонсов; nil — встроенные
+	Transport    http.RoundTripper // nil — http.DefaultTransport; в тестах — httpx.ReplayTransport
+}
+
+// defaultWindow — окно, если вызывающий не задал своё: 14 дней назад, 7 вперёд.
+var defaultWindow = config.WindowsConfig{}.ForSource("")
+
+// NewOptions переносит настройки источника из config.yaml в Options.
+// Окно и транспорт вызывающий задаёт сам.
+func NewOptions(cfg config.SourceConfig) Options {
+	return Options{
+		BaseURL:      cfg.BaseURL,
+		Mirrors:      cfg.Mirrors,
+		MaxPages:     cfg.MaxPages,
+		FetchDetails: cfg.FetchDetails,
+	}
+}
+
+// scanWindow возвращает окно сканирования с учётом значения по умолчанию.
+func (o Options) scanWindow() config.Window {
+	if o.Window == (config.Window{}) {
+		return defaultWindow
+	}
+	return o.Window
+}
+
+// classifier возвращает классификатор анонсов с учётом встроенного по умолчанию.
+func (o Options) classifier() *Classifier {
+	if o.Classifier == nil {
+		return DefaultClassifier()
+	}
+	return o.Classifier
+}
+
+// baseURL возвращает BaseURL без завершающего слэша либо def.
+func (o Options) baseURL(def string) string {
+	if o.BaseURL == "" {
+		return def
+	}
+	return strings.TrimRight(o.BaseURL, "/")
+}
+
+// baseURLs возвращает основной адрес и зеркала в порядке перебора.
+func (o Options) baseURLs(def string) []string {
+	out := []string{o.baseURL(def)}
+	for _, m := range o.Mirrors {
+		out = append(out, strings.TrimRight(m, "/"))
+	}
+	return out
+}
+
+// pageFetcher строит fetcher для HTML-страниц анонсов (без зеркал и повторов
+// сверх обычных — страница не критична, её можно взять в следующий раз).
+func (o Options) pageFetcher() *fetcher {
+	return newFetcher(o.client(), http.Header{
+		"Accept":     {"text/html,application/xhtml+xml"},
+		"User-Agent": {binanceUserAgent},
+	})
+}
+
+// client строит http.Client сканера поверх Transport.
+func (o Options) client() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second, Transport: o.Transport}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register регистрирует источник под именем name. Вызывается из init()
+// файла сканера; повторная регистрация того же имени — ошибка программиста.
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if f == nil {
+		panic("scanner: Register factory is nil for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("scanner: Register called twice for " + name)
+	}
+	registry[name] = f
+}
+
+// Lookup возвращает фабрику источника по имени.
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[name]
+	return f, ok
+}
+
+// Names возвращает отсортированный список зарегистрированных источников.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build строит сканер источника name с параметрами opts.
+func Build(name string, opts Options) (Scanner, error) {
+	f, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q", name)
+	}
+	return f(opts), nil
+}
@@ -7,18 +7,21 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
+	"crypto-bot/internal/config"
 	"crypto-bot/internal/model"
 )
 
 const (
 	unlocksSource = "tokenunlocks"
-	// tokenUnlocksURL — публичный эндпоинт разлоков от token.unlocks.app
-	tokenUnlocksURL = "https://token.unlocks.app/api/v1/upcoming?days=7"
-	// tokenUnlocksURL2 — резервный эндпоинт
-	tokenUnlocksURL2 = "https://tokenunlocks.app/api/unlocks?days=7"
+	// tokenUnlocksBaseURL + tokenUnlocksPath — публичный эндпоинт разлоков от token.unlocks.app
+	tokenUnlocksBaseURL = "https://token.unlocks.app"
+	tokenUnlocksPath    = "/api/v1/upcoming?days=%d"
+	// tokenUnlocksURL2 — резервный эндпоинт, пробуется после основного и зеркал
+	tokenUnlocksURL2 = "https://tokenunlocks.app/api/unlocks?days=%d"
 )
 
 // unlockEvent represents a single token unlock from the tokenunlocks.app API.
@@ -28,75 +31,99 @@ type unlockEvent struct {
 	UnlockDate     string  `json:"unlockDate"`     // "2026-02-20"
 	UnlockPercent  float64 `json:"unlockPercent"`  // percentage of total supply
 	UnlockValueUSD float64 `json:"unlockValueUSD"` // approximate USD value
+	Category       string  `json:"category"`       // "Team", "Private Sale", "Ecosystem Fund", ...
+	UnlockType     string  `json:"unlockType"`     // "cliff" | "linear"
 }
 
 // UnlocksScanner fetches upcoming token unlock events from tokenunlocks.app.
 type UnlocksScanner struct {
-	client *http.Client
+	fetcher   *fetcher
+	endpoints []string // full URLs in failover order
+	window    config.Window
 }
 
 // NewUnlocksScanner constructs an UnlocksScanner with a sensible HTTP client.
-func NewUnlocksScanner() *UnlocksScanner {
+// opts.BaseURL and opts.Transport let tests point it at recorded fixtures.
+// The reserve endpoint tokenUnlocksURL2 is only appended for the production
+// host, so a test server never leaks requests to the real API.
+func NewUnlocksScanner(opts Options) *UnlocksScanner {
+	window := opts.scanWindow()
+	// The API takes the horizon in whole days; round up so nothing is cut off.
+	days := int((window.Horizon + 24*time.Hour - 1) / (24 * time.Hour))
+	urls := endpoints(opts.baseURLs(tokenUnlocksBaseURL), fmt.Sprintf(tokenUnlocksPath, days))
+	if opts.BaseURL == "" {
+		urls = append(urls, fmt.Sprintf(tokenUnlocksURL2, days))
+	}
 	return &UnlocksScanner{
-		client: &http.Client{Timeout: 15 * time.Second},
+		fetcher: newFetcher(opts.client(), http.Header{
+			"Accept":     {"application/json"},
+			"User-Agent": {binanceUserAgent},
+		}),
+		endpoints: urls,
+		window:    window,
 	}
 }
 
-// Scan fetches token unlock events and returns those within the next 7 days.
-// If the upstream API is unavailable the scanner logs a warning and returns an
-// empty (non-nil) slice — callers should treat this as a graceful degradation.
-func (s *UnlocksScanner) Scan(ctx context.Context) ([]model.Event, error) {
-	now := time.Now().UTC()
-	horizon := now.Add(7 * 24 * time.Hour)
+func init() {
+	Register(unlocksSource, func(opts Options) Scanner { return NewUnlocksScanner(opts) })
+}
+
+// Scan fetches token unlock events and returns those within the configured
+// window (by default the next 45 days, see windows.sources.tokenunlocks).
+// If the upstream API is unavailable the error is returned so the aggregator
+// can tell a broken source from a quiet week.
+func (s *UnlocksScanner) Scan(ctx context.Context) (Result, error) {
+	from, horizon := s.window.Range(time.Now().UTC())
 
-	unlocks, err := s.fetchUnlocks(ctx)
+	unlocks, served, err := s.fetchUnlocks(ctx)
 	if err != nil {
-		log.Printf("[tokenunlocks] warning: failed to fetch unlocks: %v", err)
-		return []model.Event{}, nil
+		return Result{}, fmt.Errorf("tokenunlocks: fetch unlocks: %w", err)
 	}
 
+	events := groupUnlocks(unlocks, from, horizon)
+	return Result{Events: events, Fetched: len(unlocks), Endpoint: served}, nil
+}
+
+// groupUnlocks parses the entries within [from, horizon]. The API lists every
+// allocation separately; one token's unlocks on the same day become one event
+// with the allocations inside.
+func groupUnlocks(unlocks []unlockEvent, from, horizon time.Time) []model.Event {
 	var events []model.Event
+	byID := make(map[string]int)
 	for _, u := range unlocks {
-		ev, ok := parseUnlock(u, now, horizon)
+		ev, ok := parseUnlock(u, from, horizon)
 		if !ok {
 			continue
 		}
+		if i, dup := byID[ev.ID]; dup {
+			events[i] = addAllocation(events[i], u)
+			continue
+		}
+		byID[ev.ID] = len(events)
 		events = append(events, ev)
 	}
-
-	return events, nil
+	return events
 }
 
-// fetchUnlocks performs the HTTP GET and decodes the tokenunlocks.app response.
-func (s *UnlocksScanner) fetchUnlocks(ctx context.Context) ([]unlockEvent, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenUnlocksURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", binanceUserAgent)
-
-	resp, err := s.client.Do(req)
+// fetchUnlocks fetches the first endpoint that answers and decodes the
+// tokenunlocks.app response. It also reports which endpoint served the data.
+func (s *UnlocksScanner) fetchUnlocks(ctx context.Context) ([]unlockEvent, string, error) {
+	body, served, err := s.fetcher.fetch(ctx, s.endpoints)
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d from tokenunlocks", resp.StatusCode)
+		return nil, "", err
 	}
 
 	var unlocks []unlockEvent
-	if err := json.NewDecoder(resp.Body).Decode(&unlocks); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := json.Unmarshal(body, &unlocks); err != nil {
+		return nil, "", fmt.Errorf("decode response from %s: %w", served, err)
 	}
 
-	return unlocks, nil
+	return unlocks, served, nil
 }
 
 // parseUnlock converts a raw unlock entry into a model.Event.
 // Returns (event, false) when the entry should be skipped.
-func parseUnlock(u unlockEvent, now, horizon time.Time) (model.Event, bool) {
+func parseUnlock(u unlockEvent, from, horizon time.Time) (model.Event, bool) {
 	if u.UnlockDate == "" {
 		return model.Event{}, false
 	}
@@ -108,40 +135,131 @@ func parseUnlock(u unlockEvent, now, horizon time.Time) (model.Event, bool) {
 		return model.Event{}, false
 	}
 
-	if eventDate.Before(now) || eventDate.After(horizon) {
+	if eventDate.Before(from) || eventDate.After(horizon) {
 		return model.Event{}, false
 	}
 
 	token := strings.ToUpper(u.Token)
+	name := strings.TrimSpace(u.Name)
+	id := makeEventID(unlocksSource, token, eventDate)
 	if token == "" {
-		token = "UNKNOWN"
+		// Without a ticker only the name tells projects apart: otherwise
+		// unnamed unlocks of different tokens on one day would be grouped
+		// into one event with their sizes summed. No name either — skip.
+		if name == "" {
+			return model.Event{}, false
+		}
+		token = model.UnknownToken
+		id = makeEventID(unlocksSource, token, eventDate) + ":" + strings.ToLower(strings.Join(strings.Fields(name), "-"))
 	}
-
-	name := u.Name
 	if name == "" {
 		name = token
 	}
 
-	details := formatUnlockDetails(u.UnlockPercent, u.UnlockValueUSD)
+	var size *model.Unlock
+	if a, ok := parseAllocation(u); ok {
+		size = &model.Unlock{Percent: a.Percent, ValueUSD: a.ValueUSD, Allocations: []model.UnlockAllocation{a}}
+	}
 
 	return model.Event{
-		ID:      makeEventID(unlocksSource, token, eventDate),
+		ID:      id,
 		Type:    model.EventUnlock,
 		Source:  unlocksSource,
 		Token:   token,
 		Title:   fmt.Sprintf("%s (%s) — разлок токенов", name, token),
-		Date:    eventDate,
+		EventAt: eventDate,
 		URL:     fmt.Sprintf("https://tokenunlocks.app/token/%s", strings.ToLower(token)),
-		Details: details,
+		Details: formatUnlockDetails(size),
+		Unlock:  size,
+
+		// Расписание разлоков даёт только дату
+		DateConfidence: model.DateOnly,
 	}, true
 }
 
+// parseAllocation returns the structured part of an unlock entry; false when
+// the entry says nothing about size, category or vesting type.
+func parseAllocation(u unlockEvent) (model.UnlockAllocation, bool) {
+	a := model.UnlockAllocation{
+		Category: unlockCategory(u.Category),
+		Kind:     vestingKind(u.UnlockType),
+		Percent:  max(u.UnlockPercent, 0),
+		ValueUSD: max(u.UnlockValueUSD, 0),
+	}
+	return a, a != model.UnlockAllocation{}
+}
+
+// addAllocation adds another allocation of the same token and day to ev.
+func addAllocation(ev model.Event, u unlockEvent) model.Event {
+	a, ok := parseAllocation(u)
+	if !ok {
+		return ev
+	}
+	size := model.Unlock{}
+	if ev.Unlock != nil {
+		size = *ev.Unlock
+	}
+	size.Percent += a.Percent
+	size.ValueUSD += a.ValueUSD
+	size.Allocations = append(slices.Clip(size.Allocations), a)
+	ev.Unlock = &size
+	ev.Details = formatUnlockDetails(ev.Unlock)
+	return ev
+}
+
+// unlockCategory maps the free-form allocation name of the API onto a
+// model.UnlockCategory; unknown names become UnlockOther, no name — empty.
+func unlockCategory(name string) model.UnlockCategory {
+	n := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case n == "":
+		return ""
+	case containsAny(n, "team", "contributor", "advisor", "founder", "employee"):
+		return model.UnlockTeam
+	case containsAny(n, "investor", "seed", "private", "strategic", "series", "backer", "sale"):
+		return model.UnlockInvestors
+	case containsAny(n, "ecosystem", "community", "foundation", "treasury", "reserve", "airdrop", "incentive", "reward", "grant"):
+		return model.UnlockEcosystem
+	}
+	return model.UnlockOther
+}
+
+// vestingKind normalises the API unlock type; anything else is unknown.
+func vestingKind(s string) model.VestingKind {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "cliff":
+		return model.VestingCliff
+	case "linear":
+		return model.VestingLinear
+	}
+	return ""
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
 // formatUnlockDetails builds a human-readable details string for an unlock.
-// Example: "разлок 15% supply (~$120M)"
-func formatUnlockDetails(pct, valueUSD float64) string {
-	if pct <= 0 && valueUSD <= 0 {
+// Example: "разлок 15% supply ~$120M (team, investors)"
+func formatUnlockDetails(size *model.Unlock) string {
+	if size == nil {
 		return ""
 	}
+	pct, valueUSD := size.Percent, size.ValueUSD
+	var categories []string
+	for _, a := range size.Allocations {
+		if a.Category != "" && !slices.Contains(categories, string(a.Category)) {
+			categories = append(categories, string(a.Category))
+		}
+	}
+	if pct <= 0 && valueUSD <= 0 {
+		return strings.Join(categories, ", ")
+	}
 
 	var parts []string
 
@@ -164,5 +282,8 @@ func formatUnlockDetails(pct, valueUSD float64) string {
 		}
 	}
 
+	if len(categories) > 0 {
+		parts = append(parts, "("+strings.Join(categories, ", ")+")")
+	}
 	return strings.Join(parts, " ")
 }
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	backupSuffix  = ".bak"     // предыдущая удачная версия файла
+	corruptSuffix = ".corrupt" // повреждённый файл, отложенный для разбора
+)
+
+// writeFileAtomic записывает data в path так, что после сбоя на диске
+// остаётся либо старая, либо новая версия целиком: запись во временный файл
+// рядом, fsync, rename. Если backup не пуст, прежний path перед заменой
+// переименовывается в backup.
+func writeFileAtomic(path string, data []byte, backup string) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// После удачного rename удалять уже нечего — ошибка Remove не важна
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if backup != "" {
+		if err := os.Rename(path, backup); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir сбрасывает на диск запись каталога, чтобы rename пережил сбой питания
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
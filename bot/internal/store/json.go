@@ -0,0 +1,206 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// JSONStore держит события в памяти и переписывает файл целиком после
+// каждого изменения. Подходит для сотен событий; для большего — SQLite.
+//
+// Запись атомарная (временный файл + fsync + rename), а предыдущая удачная
+// версия файла остаётся в path.bak — из неё хранилище поднимается, если
+// основной файл не читается.
+type JSONStore struct {
+	path   string
+	mu     sync.Mutex
+	events map[string]model.Event // id → event
+
+	// primaryOK — основной файл цел, его можно ротировать в .bak
+	primaryOK bool
+	// recovered — почему при открытии пришлось взять резервную копию
+	recovered error
+}
+
+// OpenJSON загружает события из файла path. Отсутствующий файл (и резервной
+// копии тоже нет) — пустое хранилище, первый запуск. Если основной файл
+// повреждён, он откладывается в path.corrupt, а события берутся из path.bak;
+// ошибка — если не читается и резервная копия.
+func OpenJSON(path string) (*JSONStore, error) {
+	s := &JSONStore{path: path, events: make(map[string]model.Event)}
+
+	events, err := readEvents(path)
+	if err == nil {
+		s.primaryOK = true
+		s.fill(events)
+		log.Printf("[store] loaded %d events from %s", len(s.events), path)
+		return s, nil
+	}
+	backup := path + backupSuffix
+	if errors.Is(err, os.ErrNotExist) {
+		if _, statErr := os.Stat(backup); errors.Is(statErr, os.ErrNotExist) {
+			return s, nil
+		}
+	}
+
+	// Основной файл повреждён (или пропал между двумя rename при записи)
+	primaryErr := err
+	events, err = readEvents(backup)
+	if err != nil {
+		return nil, fmt.Errorf("%s is unreadable (%v) and so is its backup: %w", path, primaryErr, err)
+	}
+	if !errors.Is(primaryErr, os.ErrNotExist) {
+		if err := os.Rename(path, path+corruptSuffix); err != nil {
+			log.Printf("[store] failed to set aside %s: %v", path, err)
+		}
+	}
+	s.fill(events)
+	s.recovered = fmt.Errorf("%s: %v; restored %d events from %s", path, primaryErr, len(s.events), backup)
+	log.Printf("[store] CORRUPT CACHE: %v", s.recovered)
+	return s, nil
+}
+
+// Recovered возвращает описание повреждения, если при открытии события
+// восстановлены из резервной копии, иначе nil.
+func (s *JSONStore) Recovered() error {
+	return s.recovered
+}
+
+func (s *JSONStore) fill(events []model.Event) {
+	for _, e := range events {
+		s.events[e.ID] = e
+	}
+}
+
+// readEvents читает и разбирает файл событий
+func readEvents(path string) ([]model.Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var events []model.Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return events, nil
+}
+
+func (s *JSONStore) Upsert(events ...model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upsert(events)
+	return s.save()
+}
+
+func (s *JSONStore) Replace(ids []string, events ...model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.events, id)
+	}
+	s.upsert(events)
+	return s.save()
+}
+
+// upsert обновляет события в памяти, сохраняя флаги отправки; вызывается под s.mu
+func (s *JSONStore) upsert(events []model.Event) {
+	for _, e := range events {
+		if existing, ok := s.events[e.ID]; ok {
+			e.SentDigest = existing.SentDigest
+			e.Sent24h = existing.Sent24h
+			e.Sent2h = existing.Sent2h
+		}
+		s.events[e.ID] = e
+	}
+}
+
+func (s *JSONStore) Get(id string) (model.Event, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[id]
+	return e, ok, nil
+}
+
+func (s *JSONStore) Query(q Query) ([]model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []model.Event
+	for _, e := range s.events {
+		if q.match(e) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EventAt.Before(out[j].EventAt) })
+	return out, nil
+}
+
+func (s *JSONStore) Delete(ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.events, id)
+	}
+	return s.save()
+}
+
+func (s *JSONStore) MarkSent(id string, flag SentFlag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[id]
+	if !ok {
+		return nil
+	}
+	if err := setFlag(&e, flag); err != nil {
+		return err
+	}
+	s.events[id] = e
+	return s.save()
+}
+
+func (s *JSONStore) Prune(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for id, e := range s.events {
+		if e.EventAt.Before(before) {
+			delete(s.events, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.save()
+}
+
+func (s *JSONStore) Close() error { return nil }
+
+// save переписывает файл; вызывается под s.mu. Прежний файл, если он цел,
+// становится резервной копией.
+func (s *JSONStore) save() error {
+	events := make([]model.Event, 0, len(s.events))
+	for _, e := range s.events {
+		events = append(events, e)
+	}
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal events: %w", err)
+	}
+	backup := ""
+	if s.primaryOK {
+		backup = s.path + backupSuffix
+	}
+	if err := writeFileAtomic(s.path, data, backup); err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	s.primaryOK = true
+	return nil
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"crypto-bot/internal/model"
+)
+
+func TestJSONStoreContract(t *testing.T) {
+	testStoreContract(t, func(path string) (Store, error) { return OpenJSON(path + ".json") })
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.json")
+
+	if err := writeFileAtomic(path, []byte("v1"), path+backupSuffix); err != nil {
+		t.Fatal(err)
+	}
+	// Прежнего файла не было — резервной копии тоже нет
+	if _, err := os.Stat(path + backupSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backup after first write: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte("v2"), path+backupSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("v3"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{path: "v3", path + backupSuffix: "v1"} {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(name), got, err, want)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, %v; want 0644", info.Mode().Perm(), err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("leftover files: %v", names)
+	}
+}
+
+// writeEvents пишет файл событий так же, как JSONStore
+func writeEvents(t *testing.T, path string, events ...model.Event) {
+	t.Helper()
+	data, err := json.Marshal(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenJSONRecovery(t *testing.T) {
+	good := contractEvents()[:2]
+	valid, _ := json.Marshal(good)
+
+	tests := []struct {
+		name    string
+		primary []byte // nil — файла нет
+		backup  []byte
+		wantErr bool
+		want    int  // событий после открытия
+		corrupt bool // основной файл отложен в .corrupt
+	}{
+		{"first run", nil, nil, false, 0, false},
+		{"healthy", valid, nil, false, 2, false},
+		{"truncated primary", valid[:len(valid)/2], valid, false, 2, true},
+		{"garbage primary", []byte("\x00\x00\x00"), valid, false, 2, true},
+		{"empty primary", []byte{}, valid, false, 2, true},
+		{"primary lost between renames", nil, valid, false, 2, false},
+		{"both corrupt", valid[:10], []byte("{"), true, 0, false},
+		{"corrupt without backup", valid[:10], nil, true, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events.json")
+			if tt.primary != nil {
+				os.WriteFile(path, tt.primary, 0644)
+			}
+			if tt.backup != nil {
+				os.WriteFile(path+backupSuffix, tt.backup, 0644)
+			}
+
+			s, err := OpenJSON(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := s.Query(Query{})
+			if len(got) != tt.want {
+				t.Errorf("got %d events, want %d", len(got), tt.want)
+			}
+			recovered := tt.backup != nil
+			if (s.Recovered() != nil) != recovered {
+				t.Errorf("Recovered() = %v, want recovered=%v", s.Recovered(), recovered)
+			}
+			if _, err := os.Stat(path + corruptSuffix); (err == nil) != tt.corrupt {
+				t.Errorf(".corrupt exists = %v, want %v", err == nil, tt.corrupt)
+			}
+		})
+	}
+}
+
+func TestJSONStoreSaveAfterRecoveryKeepsBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	writeEvents(t, path+backupSuffix, contractEvents()[:2]...)
+	os.WriteFile(path, []byte(`[{"id": "trunc`), 0644)
+
+	s, err := OpenJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Первая запись после восстановления не ротирует: .bak остаётся
+	// последней удачной версией, а не повреждённым файлом
+	if err := s.Upsert(contractEvents()[2]); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := readEvents(path + backupSuffix)
+	if err != nil || len(backup) != 2 {
+		t.Fatalf("backup after first save: %d events, %v", len(backup), err)
+	}
+	if err := s.Upsert(contractEvents()[3]); err != nil {
+		t.Fatal(err)
+	}
+	if backup, _ = readEvents(path + backupSuffix); len(backup) != 3 {
+		t.Errorf("backup after second save: %d events, want 3", len(backup))
+	}
+
+	// Повторное открытие — основной файл цел, восстановления нет
+	s, err = OpenJSON(path)
+	if err != nil || s.Recovered() != nil {
+		t.Fatalf("reopen: %v, recovered %v", err, s.Recovered())
+	}
+	if got, _ := s.Query(Query{}); len(got) != 4 {
+		t.Errorf("reopen: %d events, want 4", len(got))
+	}
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bot/internal/model"
+
+	_ "modernc.org/sqlite" // pure-Go драйвер, без cgo
+)
+
+// sqliteDriver — имя драйвера database/sql, под которым регистрируется modernc.org/sqlite
+const sqliteDriver = "sqlite"
+
+// sqliteSchema — событие целиком лежит в data (JSON), а поля для выборок
+// и флаги отправки — в отдельных колонках. Флаги из колонок главнее data.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id          TEXT PRIMARY KEY,
+	type        TEXT    NOT NULL,
+	token       TEXT    NOT NULL,
+	event_at    INTEGER NOT NULL, -- unix, секунды UTC
+	data        TEXT    NOT NULL,
+	sent_digest INTEGER NOT NULL DEFAULT 0,
+	sent_24h    INTEGER NOT NULL DEFAULT 0,
+	sent_2h     INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS events_event_at ON events (event_at);
+CREATE INDEX IF NOT EXISTS events_token ON events (token);
+`
+
+// SQLiteStore хранит события в файле SQLite: смена флага — один UPDATE,
+// а не перезапись всего кэша.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite открывает (и при необходимости создаёт) базу path.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open(sqliteDriver, path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	// Один писатель: SQLite всё равно сериализует запись, а так не будет SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init schema %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Upsert(events ...model.Event) error {
+	return s.Replace(nil, events...)
+}
+
+func (s *SQLiteStore) Replace(ids []string, events ...model.Event) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM events WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("delete %s: %w", id, err)
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO events (id, type, token, event_at, data, sent_digest, sent_24h, sent_2h)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			type = excluded.type, token = excluded.token,
+			event_at = excluded.event_at, data = excluded.data`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", e.ID, err)
+		}
+		if _, err := stmt.Exec(e.ID, string(e.Type), strings.ToUpper(e.Token), e.EventAt.Unix(), string(data),
+			e.SentDigest, e.Sent24h, e.Sent2h); err != nil {
+			return fmt.Errorf("upsert %s: %w", e.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Get(id string) (model.Event, bool, error) {
+	rows, err := s.db.Query(`SELECT data, sent_digest, sent_24h, sent_2h FROM events WHERE id = ?`, id)
+	if err != nil {
+		return model.Event{}, false, err
+	}
+	events, err := scanEvents(rows)
+	if err != nil || len(events) == 0 {
+		return model.Event{}, false, err
+	}
+	return events[0], true, nil
+}
+
+func (s *SQLiteStore) Query(q Query) ([]model.Event, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if !q.From.IsZero() {
+		where = append(where, "event_at >= ?")
+		args = append(args, q.From.Unix())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "event_at < ?")
+		args = append(args, q.To.Unix())
+	}
+	if len(q.Types) > 0 {
+		where = append(where, "type IN (?"+strings.Repeat(", ?", len(q.Types)-1)+")")
+		for _, t := range q.Types {
+			args = append(args, string(t))
+		}
+	}
+	if q.Token != "" {
+		where = append(where, "token = ?")
+		args = append(args, strings.ToUpper(q.Token))
+	}
+
+	query := `SELECT data, sent_digest, sent_24h, sent_2h FROM events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := s.db.Query(query+" ORDER BY event_at", args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+func (s *SQLiteStore) Delete(ids ...string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM events WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("delete %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) MarkSent(id string, flag SentFlag) error {
+	var column string
+	switch flag {
+	case SentDigest:
+		column = "sent_digest"
+	case Sent24h:
+		column = "sent_24h"
+	case Sent2h:
+		column = "sent_2h"
+	default:
+		return fmt.Errorf("unknown sent flag %q", flag)
+	}
+	_, err := s.db.Exec(`UPDATE events SET `+column+` = 1 WHERE id = ?`, id)
+	return err
+}
+
+func (s *SQLiteStore) Prune(before time.Time) (int, error) {
+	res, err := s.db.Exec(`DELETE FROM events WHERE event_at < ?`, before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// scanEvents читает строки (data, sent_digest, sent_24h, sent_2h) и закрывает rows
+func scanEvents(rows *sql.Rows) ([]model.Event, error) {
+	defer rows.Close()
+	var out []model.Event
+	for rows.Next() {
+		var (
+			data                  string
+			digest, day, twoHours bool
+		)
+		if err := rows.Scan(&data, &digest, &day, &twoHours); err != nil {
+			return nil, err
+		}
+		var e model.Event
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return nil, fmt.Errorf("decode event: %w", err)
+		}
+		e.SentDigest, e.Sent24h, e.Sent2h = digest, day, twoHours
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,7 @@
+package store
+
+import "testing"
+
+func TestSQLiteStoreContract(t *testing.T) {
+	testStoreContract(t, func(path string) (Store, error) { return OpenSQLite(path + ".db") })
+}
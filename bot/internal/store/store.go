@@ -0,0 +1,105 @@
+// Package store хранит события календаря. Store — общий интерфейс,
+// реализации: JSON-файл (по умолчанию) и SQLite.
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// SentFlag — какое уведомление о событии уже отправлено
+type SentFlag string
+
+const (
+	SentDigest SentFlag = "digest"
+	Sent24h    SentFlag = "24h"
+	Sent2h     SentFlag = "2h"
+)
+
+// Query — выборка событий. Нулевые поля не ограничивают выборку.
+type Query struct {
+	From, To time.Time         // EventAt в [From, To)
+	Types    []model.EventType // любой из типов
+	Token    string            // тикер, без учёта регистра
+}
+
+// Store — хранилище событий по ID.
+type Store interface {
+	// Upsert добавляет события или обновляет существующие. Флаги отправки
+	// у уже сохранённых событий не меняются.
+	Upsert(events ...model.Event) error
+	// Replace одной операцией удаляет события ids и делает Upsert events:
+	// при сбое не остаётся ни обеих версий, ни ни одной. Флаги отправки
+	// удалённых событий не переносятся — их переносит вызывающий.
+	Replace(ids []string, events ...model.Event) error
+	// Get возвращает событие по ID; false — такого нет.
+	Get(id string) (model.Event, bool, error)
+	// Query возвращает события, подходящие под q, в порядке EventAt.
+	Query(q Query) ([]model.Event, error)
+	// Delete удаляет события по ID; отсутствующие ID пропускаются.
+	Delete(ids ...string) error
+	// MarkSent выставляет флаг отправки; отсутствующий ID — не ошибка.
+	MarkSent(id string, flag SentFlag) error
+	// Prune удаляет события раньше before и возвращает, сколько удалено.
+	Prune(before time.Time) (int, error)
+	Close() error
+}
+
+// Recoverer — хранилище, которое при открытии может подняться из резервной
+// копии. Recovered возвращает описание повреждения или nil.
+type Recoverer interface {
+	Recovered() error
+}
+
+// Бэкенды хранилища для storage.backend в config.yaml
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// Open открывает хранилище backend по пути path.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case BackendJSON, "":
+		return OpenJSON(path)
+	case BackendSQLite:
+		return OpenSQLite(path)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", backend)
+}
+
+// match сообщает, подходит ли событие под выборку
+func (q Query) match(e model.Event) bool {
+	if !q.From.IsZero() && e.EventAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.EventAt.Before(q.To) {
+		return false
+	}
+	if len(q.Types) > 0 && !slices.Contains(q.Types, e.Type) {
+		return false
+	}
+	if q.Token != "" && !strings.EqualFold(q.Token, e.Token) {
+		return false
+	}
+	return true
+}
+
+// setFlag выставляет флаг отправки у события
+func setFlag(e *model.Event, flag SentFlag) error {
+	switch flag {
+	case SentDigest:
+		e.SentDigest = true
+	case Sent24h:
+		e.Sent24h = true
+	case Sent2h:
+		e.Sent2h = true
+	default:
+		return fmt.Errorf("unknown sent flag %q", flag)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// Контракт Store: одни и те же проверки для каждого бэкенда. Бэкенд
+// подключает свой тест через testStoreContract (json_test.go, sqlite_test.go).
+
+// opener открывает хранилище по пути; повторное открытие того же пути
+// должно вернуть сохранённые события
+type opener func(path string) (Store, error)
+
+var contractBase = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func contractEvents() []model.Event {
+	return []model.Event{
+		{ID: "binance:a:PENGU", Type: model.EventListing, Source: "binance", Token: "PENGU", Title: "Binance Will List PENGU", EventAt: contractBase},
+		{ID: "tokenunlocks:ARB:20260316", Type: model.EventUnlock, Source: "tokenunlocks", Token: "ARB", EventAt: contractBase.Add(6 * 24 * time.Hour),
+			Unlock: &model.Unlock{Percent: 1.9, ValueUSD: 83_700_000}},
+		{ID: "okx:b:ZKJ", Type: model.EventListing, Source: "okx", Token: "ZKJ", EventAt: contractBase.Add(-24 * time.Hour)},
+		{ID: "bybit:c:BIRB", Type: model.EventDelisting, Source: "bybit", Token: "BIRB", EventAt: contractBase.Add(2 * time.Hour)},
+	}
+}
+
+func testStoreContract(t *testing.T, open opener) {
+	mustOpen := func(t *testing.T, path string) Store {
+		t.Helper()
+		s, err := open(path)
+		if err != nil {
+			t.Fatalf("open %s: %v", path, err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	}
+	fresh := func(t *testing.T) (Store, string) {
+		path := filepath.Join(t.TempDir(), "events")
+		s := mustOpen(t, path)
+		if err := s.Upsert(contractEvents()...); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+		return s, path
+	}
+	ids := func(events []model.Event) []string {
+		var out []string
+		for _, e := range events {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	equalIDs := func(t *testing.T, what string, got []model.Event, want ...string) {
+		t.Helper()
+		g := ids(got)
+		if len(g) != len(want) {
+			t.Fatalf("%s = %v, want %v", what, g, want)
+		}
+		for i := range want {
+			if g[i] != want[i] {
+				t.Fatalf("%s = %v, want %v", what, g, want)
+			}
+		}
+	}
+
+	t.Run("get", func(t *testing.T) {
+		s, _ := fresh(t)
+		e, ok, err := s.Get("tokenunlocks:ARB:20260316")
+		if err != nil || !ok {
+			t.Fatalf("Get = %v, %v", ok, err)
+		}
+		if e.Token != "ARB" || !e.EventAt.Equal(contractBase.Add(6*24*time.Hour)) || e.Unlock == nil || e.Unlock.ValueUSD != 83_700_000 {
+			t.Errorf("Get returned %+v", e)
+		}
+		if _, ok, err := s.Get("missing"); ok || err != nil {
+			t.Errorf("Get(missing) = %v, %v; want false, nil", ok, err)
+		}
+	})
+
+	t.Run("upsert keeps sent flags", func(t *testing.T) {
+		s, _ := fresh(t)
+		if err := s.MarkSent("binance:a:PENGU", Sent24h); err != nil {
+			t.Fatal(err)
+		}
+		updated := contractEvents()[0]
+		updated.Title = "Binance Will List Pudgy Penguins (PENGU)"
+		if err := s.Upsert(updated); err != nil {
+			t.Fatal(err)
+		}
+		e, _, _ := s.Get(updated.ID)
+		if e.Title != updated.Title || !e.Sent24h || e.Sent2h || e.SentDigest {
+			t.Errorf("after upsert: title %q, flags digest=%v 24h=%v 2h=%v", e.Title, e.SentDigest, e.Sent24h, e.Sent2h)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		s, path := fresh(t)
+		for _, id := range []string{"binance:a:PENGU", "okx:b:ZKJ"} {
+			if err := s.MarkSent(id, Sent24h); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Перенос даты с новым ID и с прежним: флаги обеих версий сброшены,
+		// флаги обычного Upsert — сохранены
+		rekeyed := contractEvents()[0]
+		rekeyed.ID = "binance:a2:PENGU"
+		moved := contractEvents()[1]
+		moved.EventAt = moved.EventAt.Add(24 * time.Hour)
+		if err := s.Replace([]string{"binance:a:PENGU", moved.ID}, rekeyed, moved); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		s = mustOpen(t, path)
+		if _, ok, _ := s.Get("binance:a:PENGU"); ok {
+			t.Error("old ID still stored")
+		}
+		for _, id := range []string{rekeyed.ID, moved.ID} {
+			e, ok, _ := s.Get(id)
+			if !ok || e.Sent24h {
+				t.Errorf("%s: stored %v, sent24h %v", id, ok, e.Sent24h)
+			}
+		}
+		if e, _, _ := s.Get(moved.ID); !e.EventAt.Equal(moved.EventAt) {
+			t.Errorf("moved event at %v, want %v", e.EventAt, moved.EventAt)
+		}
+		if all, _ := s.Query(Query{}); len(all) != len(contractEvents()) {
+			t.Errorf("got %d events, want %d", len(all), len(contractEvents()))
+		}
+	})
+
+	t.Run("mark sent", func(t *testing.T) {
+		s, _ := fresh(t)
+		for _, f := range []SentFlag{SentDigest, Sent2h} {
+			if err := s.MarkSent("okx:b:ZKJ", f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		e, _, _ := s.Get("okx:b:ZKJ")
+		if !e.SentDigest || e.Sent24h || !e.Sent2h {
+			t.Errorf("flags digest=%v 24h=%v 2h=%v", e.SentDigest, e.Sent24h, e.Sent2h)
+		}
+		if err := s.MarkSent("missing", Sent24h); err != nil {
+			t.Errorf("MarkSent(missing) = %v, want nil", err)
+		}
+		if err := s.MarkSent("okx:b:ZKJ", SentFlag("1h")); err == nil {
+			t.Error("MarkSent with unknown flag: want error")
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		s, _ := fresh(t)
+		all, err := s.Query(Query{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		equalIDs(t, "Query{}", all, "okx:b:ZKJ", "binance:a:PENGU", "bybit:c:BIRB", "tokenunlocks:ARB:20260316")
+
+		// [From, To): событие ровно в To не входит
+		got, _ := s.Query(Query{From: contractBase, To: contractBase.Add(6 * 24 * time.Hour)})
+		equalIDs(t, "Query{From,To}", got, "binance:a:PENGU", "bybit:c:BIRB")
+
+		got, _ = s.Query(Query{Types: []model.EventType{model.EventUnlock, model.EventDelisting}})
+		equalIDs(t, "Query{Types}", got, "bybit:c:BIRB", "tokenunlocks:ARB:20260316")
+
+		got, _ = s.Query(Query{Token: "zkj"})
+		equalIDs(t, "Query{Token}", got, "okx:b:ZKJ")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s, _ := fresh(t)
+		if err := s.Delete("okx:b:ZKJ", "missing", "bybit:c:BIRB"); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := s.Query(Query{})
+		equalIDs(t, "after Delete", got, "binance:a:PENGU", "tokenunlocks:ARB:20260316")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		s, _ := fresh(t)
+		n, err := s.Prune(contractBase.Add(time.Hour))
+		if err != nil || n != 2 {
+			t.Fatalf("Prune = %d, %v; want 2", n, err)
+		}
+		got, _ := s.Query(Query{})
+		equalIDs(t, "after Prune", got, "bybit:c:BIRB", "tokenunlocks:ARB:20260316")
+		if n, err := s.Prune(contractBase); n != 0 || err != nil {
+			t.Errorf("second Prune = %d, %v; want 0", n, err)
+		}
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		s, path := fresh(t)
+		if err := s.MarkSent("bybit:c:BIRB", SentDigest); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		s = mustOpen(t, path)
+		got, _ := s.Query(Query{})
+		equalIDs(t, "after reopen", got, "okx:b:ZKJ", "binance:a:PENGU", "bybit:c:BIRB", "tokenunlocks:ARB:20260316")
+		if e, _, _ := s.Get("bybit:c:BIRB"); !e.SentDigest {
+			t.Error("SentDigest lost after reopen")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package strategy
+
+import (
+	"fmt"
+
+	"crypto-bot/internal/model"
+)
+
+// Engine проверяет события по набору стратегий.
+type Engine struct {
+	strategies []Strategy
+}
+
+// NewEngine проверяет стратегии и строит движок; порядок стратегий — порядок
+// приоритета в результатах Evaluate.
+func NewEngine(strategies []Strategy) (*Engine, error) {
+	seen := make(map[string]bool)
+	for _, s := range strategies {
+		if err := s.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[s.ID] {
+			return nil, fmt.Errorf("duplicate strategy id %q", s.ID)
+		}
+		seen[s.ID] = true
+	}
+	return &Engine{strategies: strategies}, nil
+}
+
+// Limits — наибольший порог фильтра по метрике, который бот может проверить:
+// days_to_unlock видит разлоки только в окне сканирования. Фильтр с порогом
+// дальше него не отличил бы "разлока нет" от "разлок за окном".
+type Limits map[Metric]float64
+
+// Check возвращает ошибку, если фильтр какой-то стратегии выходит за лимит
+func (l Limits) Check(e *Engine) error {
+	for _, s := range e.strategies {
+		for _, f := range s.Filters {
+			if limit, ok := l[f.Metric]; ok && f.Value > limit {
+				return fmt.Errorf("strategy %s: filter %q: %s is known only up to %g", s.ID, f, f.Metric, limit)
+			}
+		}
+	}
+	return nil
+}
+
+// Strategies возвращает стратегии движка в порядке приоритета
+func (e *Engine) Strategies() []Strategy {
+	return e.strategies
+}
+
+// FilterResult — итог одного фильтра
+type FilterResult struct {
+	Filter Filter
+	Value  float64 // значение метрики, если Known
+	Known  bool    // есть ли данные; без данных фильтр не проходит
+	Pass   bool
+}
+
+// Match — итог проверки события по одной стратегии
+type Match struct {
+	Strategy  Strategy
+	Results   []FilterResult // в порядке Strategy.Filters
+	Passed    int            // сколько фильтров прошло
+	Qualified bool           // прошло не меньше, чем требует стратегия
+}
+
+// Evaluate проверяет событие по всем стратегиям, применимым к его типу,
+// в порядке приоритета.
+func (e *Engine) Evaluate(ev model.Event, facts Facts) []Match {
+	var out []Match
+	for _, s := range e.strategies {
+		if !s.appliesTo(ev) {
+			continue
+		}
+		m := Match{Strategy: s, Results: make([]FilterResult, 0, len(s.Filters))}
+		for _, f := range s.Filters {
+			r := FilterResult{Filter: f}
+			r.Value, r.Known = facts[f.Metric]
+			r.Pass = r.Known && f.Op.apply(r.Value, f.Value)
+			if r.Pass {
+				m.Passed++
+			}
+			m.Results = append(m.Results, r)
+		}
+		m.Qualified = m.Passed >= s.required()
+		out = append(out, m)
+	}
+	return out
+}
+
+// Qualify возвращает только стратегии, которые событию подходят.
+func (e *Engine) Qualify(ev model.Event, facts Facts) []Match {
+	var out []Match
+	for _, m := range e.Evaluate(ev, facts) {
+		if m.Qualified {
+			out = append(out, m)
+		}
+	}
+	return out
+}
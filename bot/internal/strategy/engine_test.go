@@ -0,0 +1,238 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	"crypto-bot/internal/model"
+)
+
+// testStrategies — стратегии для проверки движка, не зависящие от
+// настроек встроенного strategies.yaml
+const testStrategies = `
+strategies:
+  - id: splash
+    name: Splash
+    types: [listing]
+    subtypes: [spot_listing]
+    filters:
+      - float_ratio < 20%
+      - fdv_ratio > 7
+      - days_to_unlock < 90
+    min_pass: 2
+    alerts: [24h, 2h]
+  - id: gravity
+    name: Gravity
+    types: [listing, unlock]
+    filters:
+      - fdv_ratio > 8
+      - float_ratio < 20%
+    alerts: [24h]
+  - id: tge
+    name: TGE
+    types: [airdrop]
+    filters:
+      - fdv > 500M
+      - premarket != 1
+    min_pass: 1
+    alerts: [2h]
+  - id: anytime
+    name: Anytime
+    types: [listing]
+    filters:
+      - market_cap > 0
+`
+
+func testEngine(t *testing.T) *Engine {
+	t.Helper()
+	e, err := Parse([]byte(testStrategies))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func matchIDs(ms []Match) []string {
+	var ids []string
+	for _, m := range ms {
+		ids = append(ids, m.Strategy.ID)
+	}
+	return ids
+}
+
+func TestEvaluateMinPass(t *testing.T) {
+	e := testEngine(t)
+	listing := model.Event{Type: model.EventListing, Subtype: model.SubtypeSpotListing}
+
+	tests := []struct {
+		name   string
+		facts  Facts
+		passed map[string]int
+		want   []string // прошедшие стратегии в порядке приоритета
+	}{
+		{
+			name:   "two of three, the rest unknown",
+			facts:  Facts{MetricFloatRatio: 0.15, MetricFDVRatio: 9},
+			passed: map[string]int{"splash": 2, "gravity": 2, "anytime": 0},
+			want:   []string{"splash", "gravity"},
+		},
+		{
+			name:   "min_pass 0 needs every filter",
+			facts:  Facts{MetricFloatRatio: 0.15, MetricFDVRatio: 7.5, MetricMarketCap: 40e6},
+			passed: map[string]int{"splash": 2, "gravity": 1, "anytime": 1},
+			want:   []string{"splash", "anytime"},
+		},
+		{
+			name:   "one of three is not enough",
+			facts:  Facts{MetricFloatRatio: 0.3, MetricFDVRatio: 3, MetricDaysToUnlock: 30},
+			passed: map[string]int{"splash": 1, "gravity": 0, "anytime": 0},
+		},
+		{
+			name:   "no data",
+			facts:  Facts{},
+			passed: map[string]int{"splash": 0, "gravity": 0, "anytime": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := e.Evaluate(listing, tt.facts)
+			if got := matchIDs(all); strings.Join(got, ",") != "splash,gravity,anytime" {
+				t.Fatalf("evaluated %v", got)
+			}
+			for _, m := range all {
+				if m.Passed != tt.passed[m.Strategy.ID] {
+					t.Errorf("%s passed %d, want %d", m.Strategy.ID, m.Passed, tt.passed[m.Strategy.ID])
+				}
+				if len(m.Results) != len(m.Strategy.Filters) {
+					t.Errorf("%s: %d results for %d filters", m.Strategy.ID, len(m.Results), len(m.Strategy.Filters))
+				}
+			}
+			if got := matchIDs(e.Qualify(listing, tt.facts)); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("qualified %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMissingMetricFails(t *testing.T) {
+	e := testEngine(t)
+	// days_to_unlock < 90 и premarket != 1 прошли бы на нуле — но данных нет
+	splash := e.Evaluate(model.Event{Type: model.EventListing, Subtype: model.SubtypeSpotListing}, Facts{MetricFloatRatio: 0.1})[0]
+	r := splash.Results[2]
+	if r.Filter.Metric != MetricDaysToUnlock || r.Known || r.Pass {
+		t.Errorf("days_to_unlock result = %+v, want unknown and failed", r)
+	}
+	if splash.Qualified {
+		t.Error("splash qualified on one known filter")
+	}
+
+	tge := e.Evaluate(model.Event{Type: model.EventAirdrop}, Facts{})[0]
+	if tge.Passed != 0 || tge.Qualified {
+		t.Errorf("tge without data: passed %d, qualified %v", tge.Passed, tge.Qualified)
+	}
+	tge = e.Evaluate(model.Event{Type: model.EventAirdrop}, Facts{MetricPremarket: 0})[0]
+	if !tge.Qualified || !tge.Results[1].Known {
+		t.Errorf("tge with premarket=0: %+v", tge)
+	}
+}
+
+func TestEvaluateSubtypesAndVenues(t *testing.T) {
+	e := testEngine(t)
+	facts := Facts{MetricFloatRatio: 0.1, MetricFDVRatio: 10}
+	futures := model.Event{Type: model.EventListing, Subtype: model.SubtypeFuturesLaunch}
+	if got := matchIDs(e.Qualify(futures, facts)); strings.Join(got, ",") != "gravity" {
+		t.Errorf("futures listing: %v, want only gravity", got)
+	}
+	// Спот на другой площадке того же сводного события
+	futures.Venues = []model.Venue{{Subtype: model.SubtypeFuturesLaunch}, {Subtype: model.SubtypeSpotListing}}
+	if got := matchIDs(e.Qualify(futures, facts)); strings.Join(got, ",") != "splash,gravity" {
+		t.Errorf("with spot venue: %v", got)
+	}
+	if got := e.Evaluate(model.Event{Type: model.EventDelisting}, facts); len(got) != 0 {
+		t.Errorf("delisting evaluated by %v", matchIDs(got))
+	}
+}
+
+func TestQualifyByAlert(t *testing.T) {
+	e := testEngine(t)
+	cases := []struct {
+		ev    model.Event
+		facts Facts
+		in24h []string
+		in2h  []string
+	}{
+		{
+			ev:    model.Event{Type: model.EventListing, Subtype: model.SubtypeSpotListing},
+			facts: Facts{MetricFloatRatio: 0.1, MetricFDVRatio: 10, MetricMarketCap: 1e6},
+			in24h: []string{"splash", "gravity", "anytime"},
+			in2h:  []string{"splash", "anytime"},
+		},
+		{
+			ev:    model.Event{Type: model.EventAirdrop},
+			facts: Facts{MetricFDV: 600e6},
+			in2h:  []string{"tge"},
+		},
+	}
+	for _, c := range cases {
+		for alert, want := range map[Alert][]string{Alert24h: c.in24h, Alert2h: c.in2h} {
+			var got []string
+			for _, m := range e.Qualify(c.ev, c.facts) {
+				if m.Strategy.AlertsIn(alert) {
+					got = append(got, m.Strategy.ID)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s in %s alert: %v, want %v", c.ev.Type, alert, got, want)
+			}
+		}
+	}
+}
+
+func TestParseRejectsBadStrategies(t *testing.T) {
+	valid := "  - id: x\n    name: X\n    types: [listing]\n"
+	tests := []struct {
+		name, yaml, err string
+	}{
+		{"unknown metric", valid + "    filters: [volume_spike > 3]\n", `unknown metric "volume_spike"`},
+		{"bad expression", valid + "    filters: [fdv >> 3]\n", "want <metric> <op> <number>"},
+		{"share above one", valid + "    filters: [float_ratio < 25]\n", "write 25% instead"},
+		{"percent on usd", valid + "    filters: [fdv > 5%]\n", "applies only to shares"},
+		{"min_pass too big", valid + "    filters: [fdv > 1M]\n    min_pass: 2\n", "min_pass 2 out of range"},
+		{"unknown alert", valid + "    filters: [fdv > 1M]\n    alerts: [1h]\n", `unknown alert "1h"`},
+		{"unknown type", "  - id: x\n    name: X\n    types: [ipo]\n    filters: [fdv > 1M]\n", `unknown event type "ipo"`},
+		{"no filters", valid, "no filters"},
+		{"unknown field", valid + "    filters: [fdv > 1M]\n    min_passes: 1\n", "min_passes"},
+		{"duplicate id", valid + "    filters: [fdv > 1M]\n" + valid + "    filters: [fdv > 2M]\n", `duplicate strategy id "x"`},
+		{"empty", "", "no strategies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte("strategies:\n" + tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	// Движок, собранный в коде, проверяет то же, что и Parse
+	_, err := NewEngine([]Strategy{{ID: "x", Name: "X", Types: []model.EventType{model.EventListing},
+		Filters: []Filter{{Metric: "volume_spike", Op: OpMore, Value: 3}}}})
+	if err == nil || !strings.Contains(err.Error(), "unknown metric") {
+		t.Errorf("NewEngine: err = %v", err)
+	}
+}
+
+func TestDefaultStrategies(t *testing.T) {
+	var got []string
+	for _, s := range DefaultEngine().Strategies() {
+		got = append(got, s.ID)
+	}
+	if strings.Join(got, ",") != "launchpool_harvest,token_splash,tge_short,vc_gravity" {
+		t.Errorf("default strategies = %v", got)
+	}
+	for _, s := range DefaultEngine().Strategies() {
+		if s.Position.Side != SideShort || len(s.Position.TakeProfitPct) == 0 || s.Position.TimeStop == 0 {
+			t.Errorf("%s: incomplete position %+v", s.ID, s.Position)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package strategy
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"crypto-bot/internal/model"
+)
+
+// defaultStrategies — встроенные стратегии; формат описан в самом файле
+//
+//go:embed strategies.yaml
+var defaultStrategies []byte
+
+var (
+	defaultOnce   sync.Once
+	defaultEngine *Engine
+)
+
+// DefaultEngine возвращает движок со встроенными стратегиями.
+func DefaultEngine() *Engine {
+	defaultOnce.Do(func() {
+		e, err := Parse(defaultStrategies)
+		if err != nil {
+			panic("strategy: embedded strategies.yaml: " + err.Error())
+		}
+		defaultEngine = e
+	})
+	return defaultEngine
+}
+
+// Load читает стратегии из файла path; пустой path — встроенные стратегии.
+func Load(path string) (*Engine, error) {
+	if path == "" {
+		return DefaultEngine(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read strategies: %w", err)
+	}
+	e, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return e, nil
+}
+
+// strategyDef — стратегия в том виде, в каком она записана в YAML
+type strategyDef struct {
+	ID       string               `yaml:"id"`
+	Name     string               `yaml:"name"`
+	Hint     string               `yaml:"hint"`
+	Types    []model.EventType    `yaml:"types"`
+	Subtypes []model.EventSubtype `yaml:"subtypes"`
+	Filters  []string             `yaml:"filters"`
+	MinPass  int                  `yaml:"min_pass"`
+	Manual   []string             `yaml:"manual"`
+	Position struct {
+		Side          Side      `yaml:"side"`
+		Leverage      float64   `yaml:"leverage"`
+		StopLossPct   float64   `yaml:"stop_loss_pct"`
+		TakeProfitPct []float64 `yaml:"take_profit_pct"`
+		TimeStop      string    `yaml:"time_stop"`
+	} `yaml:"position"`
+	Alerts []Alert `yaml:"alerts"`
+}
+
+// Parse разбирает YAML со стратегиями и проверяет их: неизвестные поля,
+// типы событий, метрики и выражения фильтров — ошибка.
+func Parse(data []byte) (*Engine, error) {
+	var file struct {
+		Strategies []strategyDef `yaml:"strategies"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse strategies: %w", err)
+	}
+	if len(file.Strategies) == 0 {
+		return nil, fmt.Errorf("no strategies")
+	}
+
+	list := make([]Strategy, 0, len(file.Strategies))
+	for i, d := range file.Strategies {
+		name := d.ID
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		s := Strategy{
+			ID:       d.ID,
+			Name:     d.Name,
+			Hint:     d.Hint,
+			Types:    d.Types,
+			Subtypes: d.Subtypes,
+			MinPass:  d.MinPass,
+			Manual:   d.Manual,
+			Alerts:   d.Alerts,
+			Position: Position{
+				Side:          d.Position.Side,
+				Leverage:      d.Position.Leverage,
+				StopLossPct:   d.Position.StopLossPct,
+				TakeProfitPct: d.Position.TakeProfitPct,
+			},
+		}
+		if s.Position.Side == "" {
+			s.Position.Side = SideShort
+		}
+		if d.Position.TimeStop != "" {
+			ts, err := parseDuration(d.Position.TimeStop)
+			if err != nil {
+				return nil, fmt.Errorf("strategy %s: position.time_stop: %w", name, err)
+			}
+			s.Position.TimeStop = ts
+		}
+		for _, expr := range d.Filters {
+			f, err := ParseFilter(expr)
+			if err != nil {
+				return nil, fmt.Errorf("strategy %s: %w", name, err)
+			}
+			s.Filters = append(s.Filters, f)
+		}
+		list = append(list, s)
+	}
+	return NewEngine(list)
+}
+
+// filterRe — выражение фильтра: метрика, оператор, число с необязательным
+// суффиксом (K, M, B — тысячи, миллионы, миллиарды; % — доля)
+var filterRe = regexp.MustCompile(`^\s*([a-z_]+)\s*(<=|>=|==|!=|<|>)\s*([0-9]+(?:\.[0-9]+)?)\s*([KMB%]?)\s*$`)
+
+// ParseFilter разбирает выражение фильтра: "fdv > 300M", "float_ratio < 25%",
+// "fdv_ratio > 8", "premarket == 1".
+func ParseFilter(expr string) (Filter, error) {
+	m := filterRe.FindStringSubmatch(expr)
+	if m == nil {
+		return Filter{}, fmt.Errorf("filter %q: want <metric> <op> <number>", expr)
+	}
+	f := Filter{Metric: Metric(m[1]), Op: Op(m[2])}
+	info, ok := metrics[f.Metric]
+	if !ok {
+		return Filter{}, fmt.Errorf("filter %q: unknown metric %q", expr, f.Metric)
+	}
+	v, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return Filter{}, fmt.Errorf("filter %q: %w", expr, err)
+	}
+	switch m[4] {
+	case "K":
+		v *= 1e3
+	case "M":
+		v *= 1e6
+	case "B":
+		v *= 1e9
+	case "%":
+		if info.Unit != UnitShare {
+			return Filter{}, fmt.Errorf("filter %q: %% applies only to shares (float_ratio)", expr)
+		}
+		v /= 100
+	}
+	if info.Unit == UnitShare && m[4] != "%" && v > 1 {
+		return Filter{}, fmt.Errorf("filter %q: share is 0..1, write %g%% instead", expr, v)
+	}
+	f.Value = v
+	return f, nil
+}
+
+// parseDuration понимает time.ParseDuration и дни: "48h", "7d"
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
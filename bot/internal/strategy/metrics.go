@@ -0,0 +1,98 @@
+package strategy
+
+import "crypto-bot/internal/model"
+
+// Metric — числовой показатель события, по которому фильтрует стратегия
+type Metric string
+
+const (
+	MetricFloatRatio Metric = "float_ratio" // доля предложения в обращении, 0..1
+	MetricFDV        Metric = "fdv"         // FDV, USD
+	MetricMarketCap  Metric = "market_cap"  // капитализация, USD
+	MetricFDVRatio   Metric = "fdv_ratio"   // FDV / MCap
+	// MetricDaysToUnlock — дней до ближайшего разлока токена по календарю
+	MetricDaysToUnlock Metric = "days_to_unlock"
+	// MetricPremarket — 1, если у токена есть торги Pre-Market
+	MetricPremarket Metric = "premarket"
+	// MetricUnlockShare и MetricUnlockUSD — размер самого разлока (для
+	// событий unlock): доля общего предложения 0..1 и сумма в USD
+	MetricUnlockShare Metric = "unlock_share"
+	MetricUnlockUSD   Metric = "unlock_usd"
+	// MetricUnlockAhead — доля предложения, которая разлочится за горизонт
+	// графика разлоков (unlocks.schedule_days), 0..1
+	MetricUnlockAhead Metric = "unlock_ahead"
+)
+
+// Unit — как показывать значение метрики
+type Unit int
+
+const (
+	UnitNumber Unit = iota
+	UnitUSD         // сумма в долларах
+	UnitShare       // доля 0..1, показывается в процентах
+	UnitRatio       // кратность: ×8.5
+	UnitDays        // дни
+	UnitFlag        // 1 — да, 0 — нет
+)
+
+// MetricInfo — описание метрики для алертов
+type MetricInfo struct {
+	Label string // короткое название: FDV, Float
+	Unit  Unit
+}
+
+// metrics — известные метрики; фильтр по другой метрике — ошибка конфигурации
+var metrics = map[Metric]MetricInfo{
+	MetricFloatRatio:   {"Float", UnitShare},
+	MetricFDV:          {"FDV", UnitUSD},
+	MetricMarketCap:    {"MCap", UnitUSD},
+	MetricFDVRatio:     {"FDV/MCap", UnitRatio},
+	MetricDaysToUnlock: {"До разлока", UnitDays},
+	MetricPremarket:    {"Pre-Market", UnitFlag},
+	MetricUnlockShare:  {"Разлок", UnitShare},
+	MetricUnlockUSD:    {"Разлок", UnitUSD},
+	MetricUnlockAhead:  {"Разлоки впереди", UnitShare},
+}
+
+// Info возвращает описание метрики; для неизвестной — её имя как есть
+func (m Metric) Info() MetricInfo {
+	if info, ok := metrics[m]; ok {
+		return info
+	}
+	return MetricInfo{Label: string(m)}
+}
+
+// Facts — известные значения метрик события; нет ключа — нет данных
+type Facts map[Metric]float64
+
+// FactsFrom собирает метрики, которые есть в самом событии (токеномика,
+// размер разлока). Метрики из других событий календаря (ближайший разлок,
+// Pre-Market) добавляет вызывающий.
+func FactsFrom(e model.Event) Facts {
+	f := make(Facts)
+	if u := e.Unlock; u != nil {
+		if u.Percent > 0 {
+			f[MetricUnlockShare] = u.Percent / 100
+		}
+		if u.ValueUSD > 0 {
+			f[MetricUnlockUSD] = u.ValueUSD
+		}
+	}
+	t := e.Tokenomics
+	if t == nil {
+		return f
+	}
+	if t.FloatRatio > 0 {
+		f[MetricFloatRatio] = t.FloatRatio
+	}
+	if t.FDV > 0 {
+		f[MetricFDV] = t.FDV
+	}
+	if t.MarketCap > 0 {
+		f[MetricMarketCap] = t.MarketCap
+	}
+	if r := t.FDVRatio(); r > 0 {
+		f[MetricFDVRatio] = r
+	}
+	return f
+}
@@ -0,0 +1,213 @@
+// Package strategy проверяет события календаря по фильтрам торговых
+// стратегий (strategies/*.md) и сообщает, какие стратегии применимы, с
+// разбором каждого фильтра. Стратегии описываются данными — в
+// strategies.yaml (см. Parse), а не кодом.
+package strategy
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// Strategy — одна стратегия: к каким событиям применима и какие фильтры
+// событие должно пройти.
+type Strategy struct {
+	ID    string            // напр. launchpool_harvest
+	Name  string            // для людей: Launchpool Harvest
+	Hint  string            // как входить, если стратегия применима
+	Types []model.EventType // типы событий, к которым применима
+	// Subtypes сужает Types до рынков (спот, фьючерсы); пусто — любые
+	Subtypes []model.EventSubtype
+	// Filters проверяются по порядку. Метрики без данных не проходят:
+	// стратегия применима, только если это подтверждено данными.
+	Filters []Filter
+	// MinPass — сколько фильтров должно пройти; 0 — все
+	MinPass int
+	// Manual — условия из описания стратегии, которые бот проверить не может;
+	// показываются в алерте как чек-лист
+	Manual []string
+	// Position — параметры сделки: плечо, стоп, тейки, тайм-стоп
+	Position Position
+	// Alerts — в каких алертах показывать стратегию; пусто — во всех
+	Alerts []Alert
+}
+
+// Side — направление сделки
+type Side string
+
+const (
+	SideShort Side = "short"
+	SideLong  Side = "long"
+)
+
+// Position — параметры сделки по стратегии. Проценты — движение цены от
+// входа: стоп против позиции, тейки в её пользу.
+type Position struct {
+	Side          Side
+	Leverage      float64
+	StopLossPct   float64
+	TakeProfitPct []float64 // TP1, TP2, ... по мере удаления от входа
+	TimeStop      time.Duration
+}
+
+// Alert — алерт перед событием, за сколько до начала он приходит
+type Alert string
+
+const (
+	Alert24h Alert = "24h"
+	Alert2h  Alert = "2h"
+)
+
+// Alerts — алерты, которые присылает бот
+var Alerts = []Alert{Alert24h, Alert2h}
+
+// Filter — сравнение метрики события с порогом
+type Filter struct {
+	Metric Metric
+	Op     Op
+	Value  float64
+}
+
+func (f Filter) String() string {
+	return fmt.Sprintf("%s %s %g", f.Metric, f.Op, f.Value)
+}
+
+// Op — оператор сравнения фильтра
+type Op string
+
+const (
+	OpLess    Op = "<"
+	OpLessEq  Op = "<="
+	OpMore    Op = ">"
+	OpMoreEq  Op = ">="
+	OpEqual   Op = "=="
+	OpUnequal Op = "!="
+)
+
+// Valid сообщает, известен ли оператор
+func (o Op) Valid() bool {
+	switch o {
+	case OpLess, OpLessEq, OpMore, OpMoreEq, OpEqual, OpUnequal:
+		return true
+	}
+	return false
+}
+
+// apply сравнивает значение с порогом
+func (o Op) apply(v, threshold float64) bool {
+	switch o {
+	case OpLess:
+		return v < threshold
+	case OpLessEq:
+		return v <= threshold
+	case OpMore:
+		return v > threshold
+	case OpMoreEq:
+		return v >= threshold
+	case OpEqual:
+		return v == threshold
+	case OpUnequal:
+		return v != threshold
+	}
+	return false
+}
+
+// Validate проверяет стратегию: ID, типы событий, метрики, операторы и MinPass.
+func (s Strategy) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("strategy without id")
+	}
+	if s.Name == "" {
+		return fmt.Errorf("strategy %s: name is required", s.ID)
+	}
+	if len(s.Types) == 0 {
+		return fmt.Errorf("strategy %s: no event types", s.ID)
+	}
+	for _, t := range s.Types {
+		if !t.Valid() {
+			return fmt.Errorf("strategy %s: unknown event type %q", s.ID, t)
+		}
+	}
+	for _, st := range s.Subtypes {
+		if st == "" || !st.Valid() {
+			return fmt.Errorf("strategy %s: unknown event subtype %q", s.ID, st)
+		}
+	}
+	if len(s.Filters) == 0 {
+		return fmt.Errorf("strategy %s: no filters", s.ID)
+	}
+	for _, f := range s.Filters {
+		if _, ok := metrics[f.Metric]; !ok {
+			return fmt.Errorf("strategy %s: unknown metric %q", s.ID, f.Metric)
+		}
+		if !f.Op.Valid() {
+			return fmt.Errorf("strategy %s: filter %s: unknown operator %q", s.ID, f.Metric, f.Op)
+		}
+	}
+	if s.MinPass < 0 || s.MinPass > len(s.Filters) {
+		return fmt.Errorf("strategy %s: min_pass %d out of range 0..%d", s.ID, s.MinPass, len(s.Filters))
+	}
+	for _, a := range s.Alerts {
+		if !slices.Contains(Alerts, a) {
+			return fmt.Errorf("strategy %s: unknown alert %q (24h, 2h)", s.ID, a)
+		}
+	}
+	if err := s.Position.validate(); err != nil {
+		return fmt.Errorf("strategy %s: position: %w", s.ID, err)
+	}
+	return nil
+}
+
+// validate проверяет параметры сделки; нулевая Position — не задана
+func (p Position) validate() error {
+	switch p.Side {
+	case "", SideShort, SideLong:
+	default:
+		return fmt.Errorf("unknown side %q (short, long)", p.Side)
+	}
+	if p.Leverage < 0 || p.StopLossPct < 0 || p.TimeStop < 0 {
+		return fmt.Errorf("leverage, stop loss and time stop must not be negative")
+	}
+	for i, tp := range p.TakeProfitPct {
+		if tp <= 0 {
+			return fmt.Errorf("take profit #%d must be positive", i+1)
+		}
+		if i > 0 && tp <= p.TakeProfitPct[i-1] {
+			return fmt.Errorf("take profits must increase")
+		}
+	}
+	return nil
+}
+
+// appliesTo сообщает, относится ли стратегия к событию: по типу и, если
+// заданы подтипы, по подтипу события или одной из его площадок.
+func (s Strategy) appliesTo(e model.Event) bool {
+	if !slices.Contains(s.Types, e.Type) {
+		return false
+	}
+	if len(s.Subtypes) == 0 || slices.Contains(s.Subtypes, e.Subtype) {
+		return true
+	}
+	for _, v := range e.Venues {
+		if slices.Contains(s.Subtypes, v.Subtype) {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertsIn сообщает, показывать ли стратегию в алерте a
+func (s Strategy) AlertsIn(a Alert) bool {
+	return len(s.Alerts) == 0 || slices.Contains(s.Alerts, a)
+}
+
+// required — сколько фильтров должно пройти
+func (s Strategy) required() int {
+	if s.MinPass == 0 {
+		return len(s.Filters)
+	}
+	return s.MinPass
+}
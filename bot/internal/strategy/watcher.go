@@ -0,0 +1,107 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watcher держит стратегии из файла и перечитывает файл, когда тот
+// меняется. Файл с ошибкой не применяется: остаются прежние стратегии.
+type Watcher struct {
+	path   string
+	limits Limits
+
+	mu     sync.RWMutex
+	engine *Engine
+	// modTime — версия файла, прочитанная последней (удачно или нет): одну
+	// и ту же ошибку Reload сообщает один раз
+	modTime time.Time
+}
+
+// NewWatcher загружает стратегии из path; пустой path — встроенные
+// стратегии, которые не перечитываются. Если файла path нет, туда
+// записываются встроенные стратегии — заготовка для правки. Ошибка — если
+// файл не читается или не проходит проверку, в том числе limits.
+func NewWatcher(path string, limits Limits) (*Watcher, error) {
+	w := &Watcher{path: path, limits: limits}
+	if path != "" {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := os.WriteFile(path, defaultStrategies, 0644); err != nil {
+				return nil, fmt.Errorf("write default strategies: %w", err)
+			}
+			log.Printf("[strategies] %s not found, wrote built-in strategies there", path)
+			info, err = os.Stat(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read strategies: %w", err)
+		}
+		w.modTime = info.ModTime()
+	}
+	e, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.engine = e
+	return w, nil
+}
+
+// load читает стратегии и проверяет их лимиты
+func (w *Watcher) load() (*Engine, error) {
+	e, err := Load(w.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.limits.Check(e); err != nil {
+		if w.path != "" {
+			return nil, fmt.Errorf("%s: %w", w.path, err)
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// Path возвращает путь к файлу стратегий; пусто — встроенные
+func (w *Watcher) Path() string {
+	return w.path
+}
+
+// Engine возвращает текущий движок
+func (w *Watcher) Engine() *Engine {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.engine
+}
+
+// Reload перечитывает файл, если он изменился с прошлой попытки.
+// true — стратегии заменены; ошибка — файл изменён, но не применён.
+func (w *Watcher) Reload() (bool, error) {
+	if w.path == "" {
+		return false, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	info, err := os.Stat(w.path)
+	if err != nil {
+		// Пропажу файла сообщаем один раз; стратегии остаются прежними
+		if w.modTime.IsZero() {
+			return false, nil
+		}
+		w.modTime = time.Time{}
+		return false, fmt.Errorf("read strategies: %w", err)
+	}
+	if info.ModTime().Equal(w.modTime) {
+		return false, nil
+	}
+	w.modTime = info.ModTime()
+	e, err := w.load()
+	if err != nil {
+		return false, err
+	}
+	w.engine = e
+	return true, nil
+}
@@ -0,0 +1,108 @@
+package strategy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherWritesDefaultAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.yaml")
+	w, err := NewWatcher(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, defaultStrategies) {
+		t.Fatalf("default not written: %v", err)
+	}
+	if n := len(w.Engine().Strategies()); n != len(DefaultEngine().Strategies()) {
+		t.Errorf("loaded %d strategies", n)
+	}
+	if ok, err := w.Reload(); ok || err != nil {
+		t.Errorf("reload unchanged file: %v, %v", ok, err)
+	}
+
+	// Правка применяется; mtime сдвигаем явно — ФС может не различить записи
+	touch := func(content string, at time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	touch(testStrategies, now.Add(time.Second))
+	if ok, err := w.Reload(); !ok || err != nil {
+		t.Fatalf("reload edited file: %v, %v", ok, err)
+	}
+	if id := w.Engine().Strategies()[0].ID; id != "splash" {
+		t.Errorf("first strategy %s, want splash", id)
+	}
+
+	// Файл с ошибкой не применяется, ошибка — один раз
+	touch("strategies:\n  - id: broken\n", now.Add(2*time.Second))
+	if ok, err := w.Reload(); ok || err == nil {
+		t.Errorf("reload broken file: %v, %v", ok, err)
+	}
+	if ok, err := w.Reload(); ok || err != nil {
+		t.Errorf("second reload of broken file: %v, %v", ok, err)
+	}
+	if id := w.Engine().Strategies()[0].ID; id != "splash" {
+		t.Errorf("strategies replaced by broken file: %s", id)
+	}
+}
+
+func TestWatcherKeepsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.yaml")
+	if err := os.WriteFile(path, []byte(testStrategies), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testStrategies {
+		t.Error("existing file overwritten")
+	}
+	if n := len(w.Engine().Strategies()); n != 4 || w.Engine().Strategies()[0].ID != "splash" {
+		t.Errorf("loaded %d strategies", n)
+	}
+}
+
+func TestWatcherChecksLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.yaml")
+	if err := os.WriteFile(path, []byte(testStrategies), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// testStrategies фильтрует days_to_unlock < 90
+	if _, err := NewWatcher(path, Limits{MetricDaysToUnlock: 45}); err == nil {
+		t.Error("days_to_unlock < 90 accepted with a 45-day unlock window")
+	}
+	w, err := NewWatcher(path, Limits{MetricDaysToUnlock: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Правка за лимит не применяется
+	edited := strings.Replace(testStrategies, "days_to_unlock < 90", "days_to_unlock < 120", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := w.Reload(); ok || err == nil {
+		t.Errorf("reload beyond limit: %v, %v", ok, err)
+	}
+	if f := w.Engine().Strategies()[0].Filters; !slices.ContainsFunc(f, func(f Filter) bool { return f.Value == 90 }) {
+		t.Errorf("filters replaced: %v", f)
+	}
+}
@@ -0,0 +1,68 @@
+package tokenomics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+// Cache запоминает ответы провайдера на ttl — почасовое обновление
+// календаря не спрашивает про один и тот же токен каждый раз. ErrNotFound
+// тоже запоминается; прочие ошибки — нет, их повторят при следующем вызове.
+type Cache struct {
+	next Provider
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry // тикер в верхнем регистре → ответ
+}
+
+type cacheEntry struct {
+	t        model.Tokenomics
+	notFound bool
+	at       time.Time
+}
+
+// NewCache оборачивает провайдер кэшем с временем жизни записи ttl.
+func NewCache(next Provider, ttl time.Duration) *Cache {
+	return &Cache{next: next, ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cache) Lookup(ctx context.Context, token string) (model.Tokenomics, error) {
+	key := strings.ToUpper(token)
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Sub(e.at) < c.ttl {
+		if e.notFound {
+			return model.Tokenomics{}, ErrNotFound
+		}
+		return e.t, nil
+	}
+
+	t, err := c.next.Lookup(ctx, key)
+	switch {
+	case err == nil:
+		e = cacheEntry{t: t, at: now}
+	case errors.Is(err, ErrNotFound):
+		e = cacheEntry{notFound: true, at: now}
+	default:
+		return model.Tokenomics{}, err
+	}
+	c.mu.Lock()
+	c.entries[key] = e
+	// Заодно выбрасываем протухшие записи, чтобы кэш не рос бесконечно
+	for k, old := range c.entries {
+		if now.Sub(old.at) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.mu.Unlock()
+	return t, err
+}
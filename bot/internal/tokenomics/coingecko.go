@@ -0,0 +1,184 @@
+package tokenomics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bot/internal/model"
+)
+
+const (
+	coinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+	// coinGeckoPause — пауза между запросами: бесплатный лимит — около 30 в минуту
+	coinGeckoPause   = 2 * time.Second
+	coinGeckoTimeout = 15 * time.Second
+	coinGeckoMaxBody = 4 << 20
+)
+
+// CoinGecko ищет токеномику через CoinGecko-совместимый REST API: тикер
+// сначала превращается в ID монеты (/search, при совпадении тикеров —
+// монета с лучшим рангом по капитализации), затем берутся рыночные данные
+// (/coins/markets).
+type CoinGecko struct {
+	client *http.Client
+	base   string
+	key    string
+
+	mu    sync.Mutex
+	ids   map[string]string // тикер → найденный ID монеты
+	pause time.Duration     // пауза между запросами
+	next  time.Time         // раньше этого момента следующий запрос не уходит
+}
+
+// NewCoinGecko строит провайдер; base пусто — публичный API, key — демо-
+// или pro-ключ (pro-ключ — вместе с base https://pro-api.coingecko.com/api/v3).
+func NewCoinGecko(base, key string) *CoinGecko {
+	if base == "" {
+		base = coinGeckoBaseURL
+	}
+	return &CoinGecko{
+		client: &http.Client{Timeout: coinGeckoTimeout},
+		base:   strings.TrimRight(base, "/"),
+		key:    key,
+		ids:    make(map[string]string),
+		pause:  coinGeckoPause,
+	}
+}
+
+func (c *CoinGecko) Lookup(ctx context.Context, token string) (model.Tokenomics, error) {
+	id, err := c.coinID(ctx, token)
+	if err != nil {
+		return model.Tokenomics{}, err
+	}
+	var markets []struct {
+		CurrentPrice float64 `json:"current_price"`
+		MarketCap    float64 `json:"market_cap"`
+		FDV          float64 `json:"fully_diluted_valuation"`
+		Circulating  float64 `json:"circulating_supply"`
+		Total        float64 `json:"total_supply"`
+		Max          float64 `json:"max_supply"` // null — не ограничен
+	}
+	if err := c.get(ctx, "/coins/markets?vs_currency=usd&ids="+url.QueryEscape(id), &markets); err != nil {
+		return model.Tokenomics{}, err
+	}
+	if len(markets) == 0 {
+		return model.Tokenomics{}, fmt.Errorf("coingecko %s (%s): %w", token, id, ErrNotFound)
+	}
+	m := markets[0]
+	return complete(model.Tokenomics{
+		Source:      ProviderCoinGecko,
+		Circulating: m.Circulating,
+		Total:       m.Total,
+		Max:         m.Max,
+		Price:       m.CurrentPrice,
+		MarketCap:   m.MarketCap,
+		FDV:         m.FDV,
+		UpdatedAt:   time.Now().UTC(),
+	}), nil
+}
+
+// coinID находит ID монеты по тикеру. Найденные ID запоминаются навсегда;
+// "не найдена" — нет: монета может появиться в поиске после листинга, а
+// повторные вопросы про неё на ttl гасит Cache.
+func (c *CoinGecko) coinID(ctx context.Context, token string) (string, error) {
+	token = strings.ToUpper(token)
+	c.mu.Lock()
+	id, ok := c.ids[token]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	var res struct {
+		Coins []struct {
+			ID   string `json:"id"`
+			Sym  string `json:"symbol"`
+			Rank int    `json:"market_cap_rank"` // null — без ранга
+		} `json:"coins"`
+	}
+	if err := c.get(ctx, "/search?query="+url.QueryEscape(token), &res); err != nil {
+		return "", err
+	}
+	bestRank := 0
+	for _, coin := range res.Coins {
+		if !strings.EqualFold(coin.Sym, token) {
+			continue
+		}
+		// Монета без ранга проигрывает любой ранжированной
+		if id == "" || (coin.Rank > 0 && (bestRank == 0 || coin.Rank < bestRank)) {
+			id, bestRank = coin.ID, coin.Rank
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("coingecko %s: %w", token, ErrNotFound)
+	}
+	c.mu.Lock()
+	c.ids[token] = id
+	c.mu.Unlock()
+	return id, nil
+}
+
+// get выполняет запрос с паузой между запросами и разбирает ответ в v
+func (c *CoinGecko) get(ctx context.Context, path string, v any) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+path, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.key != "" {
+		if strings.Contains(c.base, "pro-api") {
+			req.Header.Set("x-cg-pro-api-key", c.key)
+		} else {
+			req.Header.Set("x-cg-demo-api-key", c.key)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("coingecko: status %d from %s", resp.StatusCode, path)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, coinGeckoMaxBody))
+	if err != nil {
+		return fmt.Errorf("read body from %s: %w", path, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode coingecko %s: %w", path, err)
+	}
+	return nil
+}
+
+// wait выдерживает паузу между запросами или ждёт отмены ctx
+func (c *CoinGecko) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	at := c.next
+	if at.Before(now) {
+		at = now
+	}
+	c.next = at.Add(c.pause)
+	c.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package tokenomics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCoinGeckoRetriesNotFound(t *testing.T) {
+	listed := false // монета появляется в поиске после листинга
+	searches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			searches++
+			if !listed {
+				io.WriteString(w, `{"coins":[{"id":"kite-other","symbol":"KITEX","market_cap_rank":900}]}`)
+				return
+			}
+			io.WriteString(w, `{"coins":[
+				{"id":"kite-fake","symbol":"KITE","market_cap_rank":null},
+				{"id":"kite-ai","symbol":"KITE","market_cap_rank":120},
+				{"id":"kite-old","symbol":"kite","market_cap_rank":2500}]}`)
+		case "/coins/markets":
+			if got := r.URL.Query().Get("ids"); got != "kite-ai" {
+				t.Errorf("markets for %q, want kite-ai", got)
+			}
+			io.WriteString(w, `[{"current_price":0.1,"market_cap":150000000,"fully_diluted_valuation":1000000000,
+				"circulating_supply":1500000000,"total_supply":10000000000,"max_supply":10000000000}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := NewCoinGecko(srv.URL, "")
+	c.pause = 0
+
+	if _, err := c.Lookup(context.Background(), "kite"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("before listing: err = %v, want ErrNotFound", err)
+	}
+	listed = true
+	got, err := c.Lookup(context.Background(), "KITE")
+	if err != nil {
+		t.Fatalf("after listing: %v", err)
+	}
+	if got.Source != ProviderCoinGecko || got.MarketCap != 150e6 || got.Max != 10e9 {
+		t.Errorf("tokenomics = %+v", got)
+	}
+	// Найденный ID больше не ищется
+	if _, err := c.Lookup(context.Background(), "KITE"); err != nil {
+		t.Fatal(err)
+	}
+	if searches != 2 {
+		t.Errorf("searches = %d, want 2", searches)
+	}
+}
@@ -0,0 +1,83 @@
+package tokenomics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"crypto-bot/internal/model"
+)
+
+// Static отдаёт токеномику из YAML-файла — для тестов и для токенов,
+// которых нет у внешнего провайдера. Формат:
+//
+//	VANA:
+//	  circulating: 30000000
+//	  total: 120000000
+//	  max: 120000000
+//	  price: 7.5
+//	  # market_cap, fdv, float_ratio — если не заданы, считаются из цены и предложения
+type Static struct {
+	tokens   map[string]model.Tokenomics
+	loadedAt time.Time
+}
+
+// staticEntry — запись файла; поля как у model.Tokenomics
+type staticEntry struct {
+	Circulating float64 `yaml:"circulating"`
+	Total       float64 `yaml:"total"`
+	Max         float64 `yaml:"max"`
+	Price       float64 `yaml:"price"`
+	MarketCap   float64 `yaml:"market_cap"`
+	FDV         float64 `yaml:"fdv"`
+	FloatRatio  float64 `yaml:"float_ratio"`
+}
+
+// LoadStatic читает файл path.
+func LoadStatic(path string) (*Static, error) {
+	if path == "" {
+		return nil, fmt.Errorf("static tokenomics: file is not set")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tokenomics file: %w", err)
+	}
+	var raw map[string]staticEntry
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse tokenomics file %s: %w", path, err)
+	}
+	s := &Static{tokens: make(map[string]model.Tokenomics, len(raw)), loadedAt: time.Now().UTC()}
+	for token, e := range raw {
+		if e.Circulating < 0 || e.Total < 0 || e.Max < 0 || e.Price < 0 {
+			return nil, fmt.Errorf("tokenomics file %s: %s: negative value", path, token)
+		}
+		if e.Max > 0 && e.Circulating > e.Max {
+			return nil, fmt.Errorf("tokenomics file %s: %s: circulating exceeds max", path, token)
+		}
+		s.tokens[strings.ToUpper(token)] = model.Tokenomics{
+			Source:      ProviderStatic,
+			Circulating: e.Circulating,
+			Total:       e.Total,
+			Max:         e.Max,
+			Price:       e.Price,
+			MarketCap:   e.MarketCap,
+			FDV:         e.FDV,
+			FloatRatio:  e.FloatRatio,
+		}
+	}
+	return s, nil
+}
+
+func (s *Static) Lookup(_ context.Context, token string) (model.Tokenomics, error) {
+	t, ok := s.tokens[strings.ToUpper(token)]
+	if !ok {
+		return model.Tokenomics{}, ErrNotFound
+	}
+	t = complete(t)
+	t.UpdatedAt = s.loadedAt
+	return t, nil
+}
@@ -0,0 +1,69 @@
+// Package tokenomics ищет предложение и оценку токенов (supply, MCap, FDV,
+// float) у внешнего провайдера. Provider — общий интерфейс, реализации:
+// CoinGecko-совместимый REST API и статический YAML-файл (для тестов и
+// ручных правок); Cache ограничивает частоту запросов к провайдеру.
+package tokenomics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"crypto-bot/internal/model"
+)
+
+// Provider ищет токеномику по тикеру.
+type Provider interface {
+	// Lookup возвращает токеномику тикера token; ErrNotFound — провайдер
+	// такого токена не знает.
+	Lookup(ctx context.Context, token string) (model.Tokenomics, error)
+}
+
+// ErrNotFound — провайдер не знает токен
+var ErrNotFound = errors.New("token not found")
+
+// Провайдеры для tokenomics.provider в config.yaml
+const (
+	ProviderCoinGecko = "coingecko"
+	ProviderStatic    = "static"
+	ProviderNone      = "none" // обогащение выключено
+)
+
+// Options — параметры провайдера
+type Options struct {
+	BaseURL string // CoinGecko: адрес API; пусто — публичный
+	APIKey  string // CoinGecko: демо- или pro-ключ; пусто — без ключа
+	File    string // static: путь к YAML
+}
+
+// New строит провайдер по имени; для ProviderNone — nil.
+func New(name string, opts Options) (Provider, error) {
+	switch name {
+	case ProviderCoinGecko, "":
+		return NewCoinGecko(opts.BaseURL, opts.APIKey), nil
+	case ProviderStatic:
+		return LoadStatic(opts.File)
+	case ProviderNone:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown tokenomics provider %q", name)
+}
+
+// complete досчитывает производные поля, которые провайдер не прислал:
+// MCap и FDV из цены и предложения, долю в обращении.
+func complete(t model.Tokenomics) model.Tokenomics {
+	supply := t.Max
+	if supply == 0 {
+		supply = t.Total
+	}
+	if t.MarketCap == 0 {
+		t.MarketCap = t.Price * t.Circulating
+	}
+	if t.FDV == 0 {
+		t.FDV = t.Price * supply
+	}
+	if t.FloatRatio == 0 && supply > 0 {
+		t.FloatRatio = t.Circulating / supply
+	}
+	return t
+}